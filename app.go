@@ -3,6 +3,7 @@ package app
 
 import (
 	"fmt"
+	"os"
 	"reflect"
 	"strings"
 
@@ -21,27 +22,42 @@ var (
 	// is mounted.
 	ErrCompoNotMounted = errors.New("component not mounted")
 
+	// ErrRefNotSet describes an error that reports whether a reference is
+	// bound to a node.
+	ErrRefNotSet = errors.New("reference not set")
+
+	// ErrNotFound describes an error that occurs when a stored value does
+	// not exist.
+	ErrNotFound = errors.New("not found")
+
 	// Logger is a function that formats using the default formats for its
 	// operands and logs the resulting string.
 	// It is used by Log, Logf, Panic and Panicf to generate logs.
 	Logger func(format string, a ...interface{})
 
-	driver  Driver
-	factory = NewFactory()
-	events  = newEventRegistry(CallOnUIGoroutine)
-	actions = newActionRegistry(events)
+	driver     Driver
+	factory    = NewFactory()
+	events     = newEventRegistry(CallOnUIGoroutine)
+	actions    = newActionRegistry(events)
+	jsFuncs    = newJSFuncRegistry()
+	validators = newValidatorRegistry()
+	i18n       = newI18nRegistry()
+	profilers  = newProfilerRegistry()
 
 	whenDebug func(func())
 )
 
 func init() {
 	EnableDebug(true)
+	factory.RegisterCompo(&VirtualList{})
 }
 
 // Import imports the component into the app.
 // Components must be imported in order the be used by the app package.
 // This allows components to be created dynamically when they are found into
 // markup.
+//
+// Run goapp vet to check the templates of all the imported components.
 func Import(c Compo) {
 	if _, err := factory.RegisterCompo(c); err != nil {
 		Panicf("import component failed: %s", err)
@@ -59,9 +75,27 @@ func Run(d Driver, addons ...Addon) error {
 	}
 
 	driver = d
+
+	if len(os.Getenv("GOAPP_VET")) != 0 {
+		vet()
+	}
+
 	return driver.Run(factory)
 }
 
+// vet reports the mistakes found in the imported components templates and
+// exits. It is called instead of running the driver when the app is launched
+// by goapp vet.
+func vet() {
+	err := factory.Lint()
+	if err == nil {
+		os.Exit(0)
+	}
+
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
+
 // RunningDriver returns the running driver.
 func RunningDriver() Driver {
 	return driver
@@ -91,6 +125,15 @@ func Storage(path ...string) string {
 	return driver.Storage(path...)
 }
 
+// LocalStore returns the store where the app persists its data.
+// Desktop apps store data in files under the storage directory. Web apps
+// store values in the browser local storage and blobs in IndexedDB.
+//
+// It panics if called before Run.
+func LocalStore() Store {
+	return driver.LocalStore()
+}
+
 // Render renders the given component.
 // It should be called when the display of component c have to be updated.
 //
@@ -36,6 +36,7 @@ func TestApp(t *testing.T) {
 		assert.NotEmpty(t, app.Name())
 		assert.Equal(t, filepath.Join("resources", "hello", "world"), app.Resources("hello", "world"))
 		assert.Equal(t, filepath.Join("storage", "hello", "world"), app.Storage("hello", "world"))
+		assert.NotNil(t, app.LocalStore())
 
 		app.Render(&tests.Hello{})
 		assert.NotNil(t, app.ElemByCompo(&tests.Hello{}))
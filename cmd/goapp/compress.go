@@ -0,0 +1,82 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// compressedExts are the extensions of the resources that are precompressed.
+// Images, fonts and media files are already compressed.
+var compressedExts = map[string]bool{
+	".css":         true,
+	".html":        true,
+	".js":          true,
+	".json":        true,
+	".map":         true,
+	".svg":         true,
+	".txt":         true,
+	".wasm":        true,
+	".webmanifest": true,
+	".xml":         true,
+}
+
+// compressResources creates a gzip and a brotli version of the compressible
+// resources. They are served by the web driver according to the encodings
+// accepted by the browsers.
+// Brotli versions are created only when the brotli command is installed.
+func (pkg *webPackage) compressResources(ctx context.Context) error {
+	var filenames []string
+
+	err := filepath.Walk(pkg.resources, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !compressedExts[filepath.Ext(path)] {
+			return nil
+		}
+
+		filenames = append(filenames, path)
+		return gzipFile(path+".gz", path)
+	})
+	if err != nil || len(filenames) == 0 {
+		return err
+	}
+
+	if _, err = exec.LookPath("brotli"); err != nil {
+		printVerbose("brotli not found: resources are only compressed with gzip")
+		return nil
+	}
+
+	args := append([]string{"--force", "--keep", "--best"}, filenames...)
+	return execute(ctx, "brotli", args...)
+}
+
+func gzipFile(dst, src string) error {
+	s, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	d, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	w, err := gzip.NewWriterLevel(d, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(w, s); err != nil {
+		return err
+	}
+
+	return w.Close()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-compress")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pkg := &webPackage{
+		resources: dir,
+	}
+
+	write := func(name, content string) {
+		name = filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0755))
+		require.NoError(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+
+	write("css/main.css", "body {}")
+	write("logo.png", "png")
+
+	require.NoError(t, pkg.compressResources(context.Background()))
+
+	f, err := os.Open(filepath.Join(dir, "css", "main.css.gz"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+
+	content, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, "body {}", string(content))
+
+	_, err = os.Stat(filepath.Join(dir, "logo.png.gz"))
+	assert.True(t, os.IsNotExist(err))
+
+	if _, err = exec.LookPath("brotli"); err != nil {
+		return
+	}
+
+	_, err = os.Stat(filepath.Join(dir, "css", "main.css.br"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/murlokswarm/app/internal/file"
+)
+
+// assetsFile is the file that maps the resources to their fingerprinted copy.
+// It must match the one read by the web driver.
+const assetsFile = "goapp-assets.json"
+
+// fingerprintedExts are the extensions of the resources that are
+// fingerprinted.
+var fingerprintedExts = map[string]bool{
+	".css":  true,
+	".js":   true,
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".svg":  true,
+	".webp": true,
+	".ico":  true,
+}
+
+// fingerprintResources creates a copy of the css, javascript and image
+// resources with the hash of their content in their name. Fingerprinted
+// copies are served with long-lived caching since their content never
+// changes.
+func (pkg *webPackage) fingerprintResources() error {
+	assets := make(map[string]string)
+
+	err := filepath.Walk(pkg.resources, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || !fingerprintedExts[filepath.Ext(path)] {
+			return nil
+		}
+
+		hash, err := fileHash(path)
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(pkg.resources, path)
+		if err != nil {
+			return err
+		}
+
+		fingerprint := fingerprintedName(rel, hash)
+		assets[filepath.ToSlash(rel)] = filepath.ToSlash(fingerprint)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for name, fingerprint := range assets {
+		src := filepath.Join(pkg.resources, filepath.FromSlash(name))
+		dst := filepath.Join(pkg.resources, filepath.FromSlash(fingerprint))
+
+		if err = file.Copy(dst, src); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(assets, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(pkg.resources, assetsFile), b, 0644)
+}
+
+// fingerprintedName returns the given filename with the given hash inserted
+// before its extension.
+func fingerprintedName(filename, hash string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "." + hash + ext
+}
+
+func fileHash(filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha1.New()
+	if _, err = io.Copy(h, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil))[:10], nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFingerprintResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-fingerprint")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pkg := &webPackage{
+		resources: dir,
+	}
+
+	write := func(name, content string) {
+		name = filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0755))
+		require.NoError(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+
+	write("css/main.css", "body {}")
+	write("goapp.js", "client")
+	write("goapp.wasm", "wasm")
+	write("locales/en.json", "{}")
+
+	require.NoError(t, pkg.fingerprintResources())
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, assetsFile))
+	require.NoError(t, err)
+
+	var assets map[string]string
+	require.NoError(t, json.Unmarshal(data, &assets))
+	require.Len(t, assets, 2)
+
+	hash, err := fileHash(filepath.Join(dir, "css", "main.css"))
+	require.NoError(t, err)
+	assert.Equal(t, "css/main."+hash+".css", assets["css/main.css"])
+	assert.Contains(t, assets, "goapp.js")
+	assert.NotContains(t, assets, "goapp.wasm")
+
+	copied, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(assets["css/main.css"])))
+	require.NoError(t, err)
+	assert.Equal(t, "body {}", string(copied))
+
+	files, _, err := precachedFiles(dir)
+	require.NoError(t, err)
+	assert.Contains(t, files, "/resources/"+assets["css/main.css"])
+	assert.NotContains(t, files, "/resources/css/main.css")
+	assert.Contains(t, files, "/resources/goapp.wasm")
+}
+
+func TestFingerprintedName(t *testing.T) {
+	assert.Equal(t, "css/main.42.css", fingerprintedName("css/main.css", "42"))
+	assert.Equal(t, "main.min.42.js", fingerprintedName("main.min.js", "42"))
+}
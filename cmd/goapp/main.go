@@ -27,6 +27,7 @@ func main() {
 			{Name: "mac", Help: "Build app for MacOS."},
 			{Name: "web", Help: "Build app for web."},
 			{Name: "win", Help: "Build app for Windows."},
+			{Name: "vet", Help: "Check the app components."},
 			{Name: "update", Help: "Update goapp to the latest version."},
 			{Name: "help", Help: "Show the help."},
 		},
@@ -45,6 +46,9 @@ func main() {
 	case "win":
 		win(ctx, args)
 
+	case "vet":
+		vet(ctx, args)
+
 	case "update":
 		update(ctx, args)
 
@@ -102,6 +106,45 @@ func update(ctx context.Context, args []string) {
 	printSuccess("goapp successfully updated")
 }
 
+type vetConfig struct {
+	Verbose bool `conf:"v" help:"Enable verbose mode."`
+}
+
+func vet(ctx context.Context, args []string) {
+	c := vetConfig{}
+
+	ld := conf.Loader{
+		Name:    "goapp vet",
+		Args:    args,
+		Usage:   "[options...] [package]",
+		Sources: []conf.Source{conf.NewEnvSource("GOAPP", os.Environ()...)},
+	}
+
+	_, roots := conf.LoadWith(&c, ld)
+	verbose = c.Verbose
+
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	printVerbose("running go vet")
+	if err := execute(ctx, "go", "vet", roots[0]); err != nil {
+		fail("%s", err)
+	}
+
+	// The app checks its imported components and exits instead of running
+	// when GOAPP_VET is set.
+	printVerbose("checking components")
+	os.Setenv("GOAPP_VET", "1")
+	defer os.Unsetenv("GOAPP_VET")
+
+	if err := execute(ctx, "go", "run", roots[0]); err != nil {
+		fail("components check failed: %s", err)
+	}
+
+	printSuccess("vet succeeded")
+}
+
 func packageRoots(packages []string) ([]string, error) {
 	if len(packages) == 0 {
 		packages = []string{"."}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// The files generated in the package resources to make a web app installable
+// and usable offline. They must match the ones served by the web driver.
+const (
+	webManifestFile   = "manifest.webmanifest"
+	serviceWorkerFile = "goapp-sw.js"
+)
+
+// webManifestConfig is the manifest configuration set in the web driver.
+type webManifestConfig struct {
+	Name            string
+	ShortName       string
+	Icon            string
+	ThemeColor      string
+	BackgroundColor string
+	Display         string
+	StartURL        string
+}
+
+// webManifest is the web app manifest.
+type webManifest struct {
+	Name            string            `json:"name"`
+	ShortName       string            `json:"short_name"`
+	StartURL        string            `json:"start_url"`
+	Display         string            `json:"display"`
+	ThemeColor      string            `json:"theme_color,omitempty"`
+	BackgroundColor string            `json:"background_color,omitempty"`
+	Icons           []webManifestIcon `json:"icons,omitempty"`
+}
+
+type webManifestIcon struct {
+	Src   string `json:"src"`
+	Sizes string `json:"sizes"`
+	Type  string `json:"type"`
+}
+
+// serviceWorker is the script that precaches the app resources. The cache is
+// versioned with the resources content: browsers install the new version and
+// delete the previous caches when the resources change.
+// Pages are fetched from the network first and fall back on the cache when
+// the app is offline.
+const serviceWorker = `const cacheName = 'goapp-{{.Version}}';
+const precached = [{{range .Files}}
+    '{{.}}',{{end}}
+];
+
+self.addEventListener('install', event => {
+    event.waitUntil(
+        caches.open(cacheName)
+            .then(cache => cache.addAll(precached))
+            .then(() => self.skipWaiting())
+    );
+});
+
+self.addEventListener('activate', event => {
+    event.waitUntil(
+        caches.keys()
+            .then(keys => Promise.all(keys
+                .filter(key => key.startsWith('goapp-') && key !== cacheName)
+                .map(key => caches.delete(key))))
+            .then(() => self.clients.claim())
+    );
+});
+
+self.addEventListener('fetch', event => {
+    const req = event.request;
+
+    if (req.method !== 'GET') {
+        return;
+    }
+
+    if (req.mode === 'navigate') {
+        event.respondWith(fetch(req)
+            .then(res => {
+                const copy = res.clone();
+                caches.open(cacheName).then(cache => cache.put(req, copy));
+                return res;
+            })
+            .catch(() => caches.match(req)
+                .then(res => res || caches.match('{{.StartURL}}'))));
+        return;
+    }
+
+    event.respondWith(caches.match(req).then(res => res || fetch(req)));
+});
+`
+
+// generatePWA generates the web app manifest, its icons and the service
+// worker in the package resources.
+func (pkg *webPackage) generatePWA(ctx context.Context) error {
+	c, err := pkg.readManifestConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	m := webManifest{
+		Name:            c.Name,
+		ShortName:       c.ShortName,
+		StartURL:        c.StartURL,
+		Display:         c.Display,
+		ThemeColor:      c.ThemeColor,
+		BackgroundColor: c.BackgroundColor,
+	}
+
+	if len(c.Icon) != 0 {
+		if m.Icons, err = pkg.generateWebIcons(c.Icon); err != nil {
+			return err
+		}
+	}
+
+	if err = writeWebManifest(filepath.Join(pkg.resources, webManifestFile), m); err != nil {
+		return err
+	}
+
+	return writeServiceWorker(filepath.Join(pkg.resources, serviceWorkerFile), pkg.resources, m.StartURL)
+}
+
+func (pkg *webPackage) readManifestConfig(ctx context.Context) (webManifestConfig, error) {
+	manifestJSON := filepath.Join(pkg.workingDir, ".manifest.json")
+	os.Setenv("GOAPP_MANIFEST", manifestJSON)
+	defer os.Remove(manifestJSON)
+	defer os.Unsetenv("GOAPP_MANIFEST")
+
+	var c webManifestConfig
+
+	if err := execute(ctx, pkg.goExec); err != nil {
+		return c, err
+	}
+
+	data, err := ioutil.ReadFile(manifestJSON)
+	if err != nil {
+		return c, err
+	}
+
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+
+	name := strings.TrimSuffix(filepath.Base(pkg.goExec), ".exe")
+	c.Name = stringWithDefault(c.Name, name)
+	c.ShortName = stringWithDefault(c.ShortName, c.Name)
+	c.Display = stringWithDefault(c.Display, "standalone")
+	c.StartURL = stringWithDefault(c.StartURL, "/")
+	return c, nil
+}
+
+func (pkg *webPackage) generateWebIcons(icon string) ([]webManifestIcon, error) {
+	sizes := []int{192, 512}
+	resizes := make([]iconInfo, 0, len(sizes))
+	icons := make([]webManifestIcon, 0, len(sizes))
+
+	for _, s := range sizes {
+		name := fmt.Sprintf("goapp-icon-%v.png", s)
+
+		resizes = append(resizes, iconInfo{
+			Name:   filepath.Join(pkg.resources, name),
+			Width:  s,
+			Height: s,
+			Scale:  1,
+		})
+
+		icons = append(icons, webManifestIcon{
+			Src:   "/resources/" + name,
+			Sizes: fmt.Sprintf("%vx%v", s, s),
+			Type:  "image/png",
+		})
+	}
+
+	err := generateIcons(filepath.Join(pkg.buildResources, icon), resizes)
+	return icons, err
+}
+
+func writeWebManifest(filename string, m webManifest) error {
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, b, 0644)
+}
+
+// writeServiceWorker writes a service worker that precaches the files in the
+// given resources directory and the start url.
+func writeServiceWorker(filename, resources, startURL string) error {
+	files, version, err := precachedFiles(resources)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	tmpl := template.Must(template.New("serviceWorker").Parse(serviceWorker))
+
+	if err = tmpl.Execute(&b, struct {
+		Version  string
+		Files    []string
+		StartURL string
+	}{
+		Version:  version,
+		Files:    append([]string{startURL}, files...),
+		StartURL: startURL,
+	}); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, b.Bytes(), 0644)
+}
+
+// precachedFiles returns the urls of the files in the given resources
+// directory and a version that changes when their content changes.
+// Resources that have a fingerprinted copy are not precached since pages
+// load the copy.
+func precachedFiles(resources string) ([]string, string, error) {
+	var files []string
+	var assets map[string]string
+	h := sha1.New()
+
+	if data, err := ioutil.ReadFile(filepath.Join(resources, assetsFile)); err == nil {
+		if err = json.Unmarshal(data, &assets); err != nil {
+			return nil, "", err
+		}
+	}
+
+	err := filepath.Walk(resources, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() || filepath.Base(path) == serviceWorkerFile {
+			return nil
+		}
+
+		rel, err := filepath.Rel(resources, path)
+		if err != nil {
+			return err
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		io.WriteString(h, rel)
+		if _, err = io.Copy(h, f); err != nil {
+			return err
+		}
+
+		if _, ok := assets[filepath.ToSlash(rel)]; !ok {
+			files = append(files, "/resources/"+filepath.ToSlash(rel))
+		}
+		return nil
+	})
+
+	return files, hex.EncodeToString(h.Sum(nil))[:12], err
+}
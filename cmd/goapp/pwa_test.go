@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteServiceWorker(t *testing.T) {
+	resources, err := ioutil.TempDir("", "goapp-pwa")
+	require.NoError(t, err)
+	defer os.RemoveAll(resources)
+
+	write := func(name, content string) {
+		name = filepath.Join(resources, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0755))
+		require.NoError(t, ioutil.WriteFile(name, []byte(content), 0644))
+	}
+
+	write("goapp.js", "client")
+	write("css/main.css", "body {}")
+
+	sw := filepath.Join(resources, serviceWorkerFile)
+	require.NoError(t, writeServiceWorker(sw, resources, "/"))
+
+	data, err := ioutil.ReadFile(sw)
+	require.NoError(t, err)
+	script := string(data)
+
+	assert.Contains(t, script, "'/',")
+	assert.Contains(t, script, "'/resources/goapp.js',")
+	assert.Contains(t, script, "'/resources/css/main.css',")
+	assert.NotContains(t, script, serviceWorkerFile)
+
+	files, version, err := precachedFiles(resources)
+	require.NoError(t, err)
+	assert.Len(t, files, 2)
+	assert.Contains(t, script, "goapp-"+version)
+
+	// The service worker itself does not change the version.
+	_, sameVersion, err := precachedFiles(resources)
+	require.NoError(t, err)
+	assert.Equal(t, version, sameVersion)
+
+	write("goapp.js", "rebuilt client")
+	_, newVersion, err := precachedFiles(resources)
+	require.NoError(t, err)
+	assert.NotEqual(t, version, newVersion)
+}
+
+func TestGenerateWebIcons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-pwa")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	pkg := &webPackage{
+		buildResources: filepath.Join(dir, "resources"),
+		resources:      filepath.Join(dir, "test.wapp", "resources"),
+	}
+	require.NoError(t, os.MkdirAll(pkg.buildResources, 0755))
+	require.NoError(t, os.MkdirAll(pkg.resources, 0755))
+
+	f, err := os.Create(filepath.Join(pkg.buildResources, "logo.png"))
+	require.NoError(t, err)
+	require.NoError(t, png.Encode(f, image.NewRGBA(image.Rect(0, 0, 1024, 1024))))
+	f.Close()
+
+	icons, err := pkg.generateWebIcons("logo.png")
+	require.NoError(t, err)
+	require.Len(t, icons, 2)
+	assert.Equal(t, "/resources/goapp-icon-192.png", icons[0].Src)
+	assert.Equal(t, "192x192", icons[0].Sizes)
+	assert.Equal(t, "512x512", icons[1].Sizes)
+
+	for _, size := range []string{"192", "512"} {
+		_, err := os.Stat(filepath.Join(pkg.resources, "goapp-icon-"+size+".png"))
+		assert.NoError(t, err)
+	}
+
+	manifest := filepath.Join(pkg.resources, webManifestFile)
+	require.NoError(t, writeWebManifest(manifest, webManifest{
+		Name:     "test",
+		StartURL: "/",
+		Icons:    icons,
+	}))
+
+	data, err := ioutil.ReadFile(manifest)
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &m))
+	assert.Equal(t, "test", m["name"])
+	assert.Len(t, m["icons"], 2)
+	assert.NotContains(t, m, "theme_color")
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/murlokswarm/app/internal/file"
+	"github.com/pkg/errors"
+)
+
+// wasmLoader is the script that loads the go javascript support and runs the
+// WebAssembly client. It replaces the GopherJS client script.
+const wasmLoader = `(function () {
+    const script = document.createElement('script');
+    script.src = '/resources/wasm_exec.js';
+
+    script.onload = () => {
+        const go = new Go();
+        const wasm = fetch('/resources/goapp.wasm');
+
+        const instantiate = WebAssembly.instantiateStreaming
+            ? WebAssembly.instantiateStreaming(wasm, go.importObject)
+            : wasm
+                .then(res => res.arrayBuffer())
+                .then(buffer => WebAssembly.instantiate(buffer, go.importObject));
+
+        instantiate
+            .then(result => go.run(result.instance))
+            .catch(err => console.error('loading goapp.wasm failed:', err));
+    };
+
+    document.head.appendChild(script);
+})();
+`
+
+func (pkg *webPackage) buildWasm(ctx context.Context) error {
+	os.Setenv("GOOS", "js")
+	os.Setenv("GOARCH", "wasm")
+
+	err := goBuild(ctx, pkg.buildDir, "-o", pkg.wasm)
+
+	os.Unsetenv("GOOS")
+	os.Unsetenv("GOARCH")
+
+	if err != nil {
+		return err
+	}
+
+	wasmExec, err := wasmExecPath()
+	if err != nil {
+		return err
+	}
+
+	if err = file.Copy(pkg.wasmExec, wasmExec); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(pkg.gopherJS, []byte(wasmLoader), 0644)
+}
+
+// wasmExecPath returns the path of the wasm_exec.js file that comes with the
+// go distribution.
+func wasmExecPath() (string, error) {
+	goroot := executeString("go", "env", "GOROOT")
+
+	paths := []string{
+		filepath.Join(goroot, "lib", "wasm", "wasm_exec.js"),
+		filepath.Join(goroot, "misc", "wasm", "wasm_exec.js"),
+	}
+
+	for _, p := range paths {
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+
+	return "", errors.Errorf("wasm_exec.js not found in %s", goroot)
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWasmExecPath(t *testing.T) {
+	p, err := wasmExecPath()
+	require.NoError(t, err)
+	assert.Equal(t, "wasm_exec.js", filepath.Base(p))
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// watcher reports the changes of the go sources and of the resources of a
+// directory. It polls the file modification times to work the same way on
+// every platform.
+type watcher struct {
+	// The watched directory.
+	root string
+
+	// The files and directories that are not watched.
+	ignored []string
+
+	// The delay between two polls.
+	interval time.Duration
+
+	modTimes map[string]time.Time
+}
+
+func newWatcher(root string, ignored ...string) (*watcher, error) {
+	w := &watcher{
+		root:     root,
+		ignored:  ignored,
+		interval: time.Millisecond * 500,
+	}
+
+	modTimes, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+
+	w.modTimes = modTimes
+	return w, nil
+}
+
+// Wait blocks until a watched file is created, modified or removed. It
+// returns the name of a changed file.
+func (w *watcher) Wait(ctx context.Context) (string, error) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+
+		case <-ticker.C:
+		}
+
+		modTimes, err := w.scan()
+		if err != nil {
+			return "", err
+		}
+
+		changed := changedFile(w.modTimes, modTimes)
+		w.modTimes = modTimes
+
+		if len(changed) != 0 {
+			return changed, nil
+		}
+	}
+}
+
+func (w *watcher) scan() (map[string]time.Time, error) {
+	modTimes := make(map[string]time.Time)
+	resources := filepath.Join(w.root, "resources")
+
+	err := filepath.Walk(w.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if w.isIgnored(path) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := info.Name()
+
+		if info.IsDir() {
+			if path != w.root && (strings.HasPrefix(name, ".") || filepath.Ext(name) == ".wapp") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(name) == ".go" || strings.HasPrefix(path, resources+string(filepath.Separator)) {
+			modTimes[path] = info.ModTime()
+		}
+
+		return nil
+	})
+
+	return modTimes, err
+}
+
+func (w *watcher) isIgnored(path string) bool {
+	for _, ignored := range w.ignored {
+		if path == ignored {
+			return true
+		}
+	}
+
+	return false
+}
+
+// changedFile returns the name of a file that is not in both given states or
+// whose modification time changed. It returns an empty string when there is
+// no change.
+func changedFile(old, new map[string]time.Time) string {
+	for name, modTime := range new {
+		if oldModTime, ok := old[name]; !ok || !oldModTime.Equal(modTime) {
+			return name
+		}
+	}
+
+	for name := range old {
+		if _, ok := new[name]; !ok {
+			return name
+		}
+	}
+
+	return ""
+}
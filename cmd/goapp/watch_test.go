@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	root, err := ioutil.TempDir("", "goapp-watch")
+	require.NoError(t, err)
+	defer os.RemoveAll(root)
+
+	write := func(name string) string {
+		name = filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(name), 0755))
+		require.NoError(t, ioutil.WriteFile(name, []byte(time.Now().String()), 0644))
+		return name
+	}
+
+	main := write("main.go")
+	css := write("resources/css/main.css")
+	gopherJS := write("resources/goapp.js")
+	write("test.wapp/resources/goapp.js")
+
+	w, err := newWatcher(root, gopherJS)
+	require.NoError(t, err)
+	w.interval = time.Millisecond * 10
+
+	assert.Len(t, w.modTimes, 2)
+	assert.Contains(t, w.modTimes, main)
+	assert.Contains(t, w.modTimes, css)
+
+	tests := []struct {
+		scenario string
+		change   func() string
+	}{
+		{
+			scenario: "go source modified",
+			change: func() string {
+				modTime := time.Now().Add(time.Second)
+				require.NoError(t, os.Chtimes(main, modTime, modTime))
+				return main
+			},
+		},
+		{
+			scenario: "resource added",
+			change: func() string {
+				return write("resources/js/main.js")
+			},
+		},
+		{
+			scenario: "resource removed",
+			change: func() string {
+				require.NoError(t, os.Remove(css))
+				return css
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			name := test.change()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			changed, err := w.Wait(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, name, changed)
+		})
+	}
+
+	t.Run("ignored file modified", func(t *testing.T) {
+		write("resources/goapp.js")
+		write("test.wapp/resources/goapp.js")
+		write("README.md")
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+		defer cancel()
+
+		_, err := w.Wait(ctx)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
@@ -4,12 +4,14 @@ import (
 	"context"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"time"
 
 	"github.com/murlokswarm/app/internal/file"
+	"github.com/pkg/errors"
 	"github.com/segmentio/conf"
 )
 
@@ -21,6 +23,7 @@ func web(ctx context.Context, args []string) {
 			{Name: "init", Help: "Download gopherjs and create the required files and directories."},
 			{Name: "build", Help: "Build the web server and generate Gopher.js file."},
 			{Name: "run", Help: "Run the server and launch the client in the default browser."},
+			{Name: "export", Help: "Export the web app pages and resources as a static website."},
 			{Name: "help", Help: "Show the web help"},
 		},
 	}
@@ -38,6 +41,9 @@ func web(ctx context.Context, args []string) {
 	case "run":
 		runWeb(ctx, args)
 
+	case "export":
+		exportWeb(ctx, args)
+
 	default:
 		panic("unreachable")
 	}
@@ -91,9 +97,10 @@ func goGetGopherJS(ctx context.Context) error {
 }
 
 type webBuildConfig struct {
-	Output  string `conf:"o" help:"The output."`
-	Minify  bool   `conf:"m" help:"Minify gopherjs file."`
-	Verbose bool   `conf:"v" help:"Enable verbose mode."`
+	Output  string `conf:"o"    help:"The output."`
+	Minify  bool   `conf:"m"    help:"Minify gopherjs file."`
+	Wasm    bool   `conf:"wasm" help:"Build the client in WebAssembly rather than with GopherJS."`
+	Verbose bool   `conf:"v"    help:"Enable verbose mode."`
 }
 
 func buildWeb(ctx context.Context, args []string) {
@@ -128,12 +135,18 @@ func buildWeb(ctx context.Context, args []string) {
 }
 
 type webRunConfig struct {
-	Addr    string   `conf:"addr"   help:"The server bind address."`
-	Args    []string `conf:"args"   help:"The arguments to launch the server."`
-	Browser bool     `conf:"b"      help:"Run the client."`
-	Chrome  bool     `conf:"chrome" help:"Run the client with Google Chrome."`
-	Minify  bool     `conf:"m"      help:"Minify gopherjs file."`
-	Verbose bool     `conf:"v"      help:"Enable verbose mode."`
+	Addr     string   `conf:"addr"     help:"The server bind address."`
+	Args     []string `conf:"args"     help:"The arguments to launch the server."`
+	Browser  bool     `conf:"b"        help:"Run the client."`
+	Chrome   bool     `conf:"chrome"   help:"Run the client with Google Chrome."`
+	Minify   bool     `conf:"m"        help:"Minify gopherjs file."`
+	Wasm     bool     `conf:"wasm"     help:"Build the client in WebAssembly rather than with GopherJS."`
+	Watch    bool     `conf:"w"        help:"Rebuild and restart the server when the sources or resources change. Browsers are reloaded."`
+	TLS      bool     `conf:"tls"      help:"Serve over HTTPS with a self-signed development certificate."`
+	Cert     string   `conf:"cert"     help:"The TLS certificate file. Serve over HTTPS when set with the key file."`
+	Key      string   `conf:"key"      help:"The TLS key file."`
+	Redirect string   `conf:"redirect" help:"The bind address of a server that redirects HTTP requests to HTTPS."`
+	Verbose  bool     `conf:"v"        help:"Enable verbose mode."`
 }
 
 func runWeb(ctx context.Context, args []string) {
@@ -157,26 +170,35 @@ func runWeb(ctx context.Context, args []string) {
 		wappname = roots[0]
 	}
 
+	var pkg *webPackage
+
 	if !strings.HasSuffix(wappname, ".wapp") {
 		printVerbose("building package")
-		pkg, err := newWebPackage(wappname, "")
-		if err != nil {
+		var err error
+		if pkg, err = newWebPackage(wappname, ""); err != nil {
 			fail("%s", err)
 		}
 
 		if err = pkg.Build(ctx, webBuildConfig{
 			Minify: c.Minify,
+			Wasm:   c.Wasm,
 		}); err != nil {
 			fail("%s", err)
 		}
 
 		wappname = pkg.name
+	} else if c.Watch {
+		fail("watch mode requires a package, not a .wapp")
 	}
 
 	server := filepath.Base(wappname)
 	server = strings.TrimSuffix(server, ".wapp")
 	server = filepath.Join(wappname, server)
 
+	if err := setupWebTLS(&c, wappname); err != nil {
+		fail("%s", err)
+	}
+
 	if c.Browser || c.Chrome {
 		go launchNavigator(ctx, c)
 	}
@@ -184,6 +206,16 @@ func runWeb(ctx context.Context, args []string) {
 	os.Setenv("GOAPP_SERVER_ADDR", c.Addr)
 	defer os.Unsetenv("GOAPP_SERVER_ADDR")
 
+	if c.Watch {
+		os.Setenv("GOAPP_HOT_RELOAD", "1")
+		defer os.Unsetenv("GOAPP_HOT_RELOAD")
+
+		if err := watchWeb(ctx, pkg, c, server); err != nil && err != context.Canceled {
+			fail("%s", err)
+		}
+		return
+	}
+
 	printVerbose("starting server")
 	if err := os.Chdir(wappname); err != nil {
 		fail("%s", err)
@@ -194,13 +226,219 @@ func runWeb(ctx context.Context, args []string) {
 	}
 }
 
+// setupWebTLS sets the environment that makes the server use TLS. A
+// development certificate is generated in the package when TLS is enabled
+// without a certificate.
+func setupWebTLS(c *webRunConfig, wappname string) error {
+	if c.TLS && len(c.Cert) == 0 {
+		c.Cert = filepath.Join(wappname, "goapp-dev.crt")
+		c.Key = filepath.Join(wappname, "goapp-dev.key")
+
+		printVerbose("generating development certificate")
+		if err := generateDevCert(c.Cert, c.Key); err != nil {
+			return err
+		}
+
+		// HSTS would make browsers use HTTPS for every localhost app.
+		os.Setenv("GOAPP_HSTS_MAX_AGE", "-1s")
+	}
+
+	if len(c.Cert) == 0 {
+		if len(c.Redirect) != 0 {
+			return errors.New("redirect requires a tls certificate")
+		}
+		return nil
+	}
+
+	if len(c.Key) == 0 {
+		return errors.New("tls certificate requires a key")
+	}
+
+	c.TLS = true
+
+	cert, err := filepath.Abs(c.Cert)
+	if err != nil {
+		return err
+	}
+
+	key, err := filepath.Abs(c.Key)
+	if err != nil {
+		return err
+	}
+
+	os.Setenv("GOAPP_TLS_CERT", cert)
+	os.Setenv("GOAPP_TLS_KEY", key)
+	os.Setenv("GOAPP_REDIRECT_ADDR", c.Redirect)
+	return nil
+}
+
+// watchWeb runs the server and restarts it each time the package is rebuilt
+// after a change of its sources or resources. The server tells the browsers
+// to reload when it restarts.
+func watchWeb(ctx context.Context, pkg *webPackage, c webRunConfig, server string) error {
+	w, err := newWatcher(pkg.buildDir, pkg.gopherJS, pkg.wasm, pkg.wasmExec, pkg.name)
+	if err != nil {
+		return err
+	}
+
+	printVerbose("starting server")
+	s, err := startWebServer(ctx, pkg.name, server, c.Args)
+	if err != nil {
+		return err
+	}
+
+	for {
+		changed, err := w.Wait(ctx)
+		if err != nil {
+			s.Stop()
+			return err
+		}
+
+		printWarn("%s changed, rebuilding", changed)
+
+		if err = pkg.Build(ctx, webBuildConfig{Minify: c.Minify, Wasm: c.Wasm}); err != nil {
+			printErr("build failed: %s", err)
+			continue
+		}
+
+		s.Stop()
+
+		if s, err = startWebServer(ctx, pkg.name, server, c.Args); err != nil {
+			return err
+		}
+
+		printSuccess("server restarted")
+	}
+}
+
+// webServer is a running web server process.
+type webServer struct {
+	cmd  *exec.Cmd
+	done chan error
+}
+
+func startWebServer(ctx context.Context, dir, name string, args []string) (*webServer, error) {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	s := &webServer{
+		cmd:  cmd,
+		done: make(chan error, 1),
+	}
+
+	go func() {
+		s.done <- cmd.Wait()
+	}()
+
+	return s, nil
+}
+
+// Stop interrupts the server and waits for it to exit. The server is killed
+// if it is still running after 5 seconds.
+func (s *webServer) Stop() {
+	if runtime.GOOS == "windows" {
+		s.cmd.Process.Kill()
+	} else {
+		s.cmd.Process.Signal(os.Interrupt)
+	}
+
+	select {
+	case <-s.done:
+	case <-time.After(time.Second * 5):
+		s.cmd.Process.Kill()
+		<-s.done
+	}
+}
+
+type webExportConfig struct {
+	Output  string   `conf:"o"      help:"The directory where the website is exported."`
+	Routes  []string `conf:"routes" help:"The routes to export. Default is the components that implement app.Static."`
+	Strip   bool     `conf:"strip"  help:"Remove the client runtime from the pages of static components."`
+	Minify  bool     `conf:"m"      help:"Minify gopherjs file."`
+	Wasm    bool     `conf:"wasm"   help:"Build the client in WebAssembly rather than with GopherJS."`
+	Verbose bool     `conf:"v"      help:"Enable verbose mode."`
+}
+
+func exportWeb(ctx context.Context, args []string) {
+	c := webExportConfig{
+		Output: "public",
+		Minify: true,
+	}
+
+	ld := conf.Loader{
+		Name:    "web export",
+		Args:    args,
+		Usage:   "[options...] [package]",
+		Sources: []conf.Source{conf.NewEnvSource("GOAPP", os.Environ()...)},
+	}
+
+	_, roots := conf.LoadWith(&c, ld)
+	verbose = c.Verbose
+
+	if len(roots) == 0 {
+		roots = []string{"."}
+	}
+
+	output, err := filepath.Abs(c.Output)
+	if err != nil {
+		fail("%s", err)
+	}
+
+	pkg, err := newWebPackage(roots[0], "")
+	if err != nil {
+		fail("%s", err)
+	}
+
+	printVerbose("building package")
+	if err = pkg.Build(ctx, webBuildConfig{
+		Minify: c.Minify,
+		Wasm:   c.Wasm,
+	}); err != nil {
+		fail("%s", err)
+	}
+
+	os.Setenv("GOAPP_EXPORT", output)
+	defer os.Unsetenv("GOAPP_EXPORT")
+
+	os.Setenv("GOAPP_EXPORT_ROUTES", strings.Join(c.Routes, ","))
+	defer os.Unsetenv("GOAPP_EXPORT_ROUTES")
+
+	if c.Strip {
+		os.Setenv("GOAPP_EXPORT_STRIP", "1")
+		defer os.Unsetenv("GOAPP_EXPORT_STRIP")
+	}
+
+	printVerbose("exporting pages")
+	s, err := startWebServer(ctx, pkg.name, pkg.goExec, nil)
+	if err != nil {
+		fail("%s", err)
+	}
+
+	if err = <-s.done; err != nil {
+		fail("export failed: %s", err)
+	}
+
+	printSuccess("website exported in %s", output)
+}
+
 func launchNavigator(ctx context.Context, c webRunConfig) {
 	time.Sleep(time.Millisecond * 250)
 	printVerbose("starting client")
 
+	scheme := "http://"
+	if c.TLS {
+		scheme = "https://"
+	}
+
 	rawurl := c.Addr
-	if !strings.HasPrefix(rawurl, "http://") {
-		rawurl = "http://" + rawurl
+	if !strings.HasPrefix(rawurl, scheme) {
+		rawurl = scheme + rawurl
 	}
 
 	u, err := url.Parse(rawurl)
@@ -270,6 +508,8 @@ type webPackage struct {
 	resources        string
 	goExec           string
 	gopherJS         string
+	wasm             string
+	wasmExec         string
 	minify           bool
 }
 
@@ -309,6 +549,8 @@ func newWebPackage(buildDir, name string) (*webPackage, error) {
 		resources:        filepath.Join(wd, name, "resources"),
 		goExec:           filepath.Join(wd, name, goExec),
 		gopherJS:         filepath.Join(buildDir, "resources", "goapp.js"),
+		wasm:             filepath.Join(buildDir, "resources", "goapp.wasm"),
+		wasmExec:         filepath.Join(buildDir, "resources", "wasm_exec.js"),
 	}, nil
 }
 
@@ -326,13 +568,35 @@ func (pkg *webPackage) Build(ctx context.Context, c webBuildConfig) error {
 		return err
 	}
 
-	printVerbose("building gopherjs client")
-	if err := pkg.buildGopherJS(ctx); err != nil {
-		return err
+	if c.Wasm {
+		printVerbose("building wasm client")
+		if err := pkg.buildWasm(ctx); err != nil {
+			return err
+		}
+	} else {
+		printVerbose("building gopherjs client")
+		if err := pkg.buildGopherJS(ctx); err != nil {
+			return err
+		}
 	}
 
 	printVerbose("syncing resources")
-	return pkg.syncResources()
+	if err := pkg.syncResources(); err != nil {
+		return err
+	}
+
+	printVerbose("fingerprinting resources")
+	if err := pkg.fingerprintResources(); err != nil {
+		return err
+	}
+
+	printVerbose("generating manifest and service worker")
+	if err := pkg.generatePWA(ctx); err != nil {
+		return err
+	}
+
+	printVerbose("compressing resources")
+	return pkg.compressResources(ctx)
 }
 
 func (pkg *webPackage) createPackage() error {
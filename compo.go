@@ -1,6 +1,9 @@
 package app
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"net/url"
 )
 
@@ -49,6 +52,17 @@ type Navigable interface {
 	OnNavigate(u *url.URL)
 }
 
+// Static is the interface that describes a component that is rendered as a
+// static page. Static components are exported by goapp web export.
+type Static interface {
+	Compo
+
+	// Static reports whether the component only displays content. Pages of
+	// components that only display content can be exported without the
+	// client runtime.
+	Static() bool
+}
+
 // Subscriber is the interface that describes a component that subscribes to
 // events generated from actions.
 type Subscriber interface {
@@ -65,13 +79,111 @@ type CompoWithExtendedRender interface {
 	Compo
 
 	// Funcs returns a map of funcs to use when rendering a component.
-	// Funcs named raw, json and time are reserved.
-	// They handle raw html code, json conversions and time format.
-	// They can't be overloaded.
+	// The following funcs are reserved and can't be overloaded:
+	//
+	//	raw       raw html code, sanitized with the driver raw policy
+	//	compo     component tag from a component name
+	//	json      json conversion
+	//	time      time format
+	//	loading   asynchronous loading state
+	//	loadErr   asynchronous loading error
+	//	t         message translation
+	//	plural    plural message translation
+	//	number    locale number format
+	//	date      locale date format
+	//	dir       locale text direction
+	//	url       url with query parameters
+	//	classes   css class list from names and maps
+	//	style     inline css from properties and maps
+	//	bytes     byte count format, like 1.5 MB
+	//	duration  short duration format, like 1h 5m
+	//	truncate  text truncation
+	//	default   default value for zero values
+	//	attr      conditional html attribute
+	//	resources resources directory path
+	//
 	// See https://golang.org/pkg/text/template/#Template.Funcs for more details.
 	Funcs() map[string]interface{}
 }
 
+// Loader is the interface that describes a component that loads its content
+// asynchronously.
+//
+// While the load is pending, the loading template func returns true. When
+// it fails, the loadErr template func returns the error:
+//
+//	{{if loading}}
+//	    <p>Loading...</p>
+//	{{else if loadErr}}
+//	    <p>{{loadErr}}</p>
+//	{{else}}
+//	    <p>{{.Content}}</p>
+//	{{end}}
+type Loader interface {
+	Compo
+
+	// Load is called on its own goroutine when the component is mounted.
+	// The context is canceled when the component is dismounted.
+	// The returned func is called on the UI goroutine to update the component
+	// with the loaded data. The component is rendered right after.
+	// Load must not modify the component directly.
+	Load(ctx context.Context) (apply func(), err error)
+}
+
+// Styler is the interface that describes a component that provides its own
+// styles.
+type Styler interface {
+	Compo
+
+	// Styles returns the CSS rules that apply to the nodes rendered by the
+	// component.
+	// Rules are scoped to the component type: they do not affect nodes rendered
+	// by other components, including child components.
+	// Styles are injected when the first component of its type is mounted and
+	// removed when the last one is dismounted.
+	Styles() string
+}
+
+// ErrorBoundary is the interface that describes a component that catches the
+// errors and panics that occur while rendering or mounting its descendants.
+type ErrorBoundary interface {
+	Compo
+
+	// OnError is called when a descendant fails to render or mount.
+	// The component is rendered again right after the call. Its Render should
+	// then return a fallback that does not contain the failing descendant.
+	// If the fallback fails too, the error is reported to the element that
+	// renders the component.
+	OnError(err CompoError)
+}
+
+// CompoError describes an error that occurred in a component.
+type CompoError struct {
+	// The name of the component where the error occurred.
+	Compo string
+
+	// The error.
+	Err error
+
+	// The stack trace of the goroutine when the error is a recovered panic.
+	Stack string
+}
+
+func (e CompoError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Compo, e.Err)
+}
+
+// Format formats the error. %+v prints the stack trace when the error is a
+// recovered panic.
+func (e CompoError) Format(s fmt.State, verb rune) {
+	io.WriteString(s, e.Error())
+
+	if verb == 'v' && s.Flag('+') && len(e.Stack) != 0 {
+		io.WriteString(s, "\n")
+		io.WriteString(s, e.Stack)
+	}
+}
+
 // ZeroCompo is the type to use as base for empty components.
 // Every instances of an empty struct is given the same memory address, which
 // causes problem for indexing components.
@@ -17,6 +17,9 @@ type Driver interface {
 	// location.
 	Storage(path ...string) string
 
+	// LocalStore returns the store where the app persists its data.
+	LocalStore() Store
+
 	// Render renders the given component.
 	Render(Compo)
 
@@ -65,13 +65,17 @@ func (d *DockTile) Load(urlFmt string, v ...interface{}) {
 		return
 	}
 
-	err := driver.macRPC.Call("docks.SetMenu", nil, struct {
-		ID string
-	}{
-		ID: d.id,
-	})
+	d.setMenu()
+}
 
-	d.SetErr(err)
+// Mount satisfies the app.DockTile interface.
+func (d *DockTile) Mount(c app.Compo) {
+	d.Menu.Mount(c)
+	if d.Err() != nil {
+		return
+	}
+
+	d.setMenu()
 }
 
 // SetIcon satisfies the app.DockTile interface.
@@ -105,3 +109,13 @@ func (d *DockTile) SetBadge(v interface{}) {
 
 	d.SetErr(err)
 }
+
+func (d *DockTile) setMenu() {
+	err := driver.macRPC.Call("docks.SetMenu", nil, struct {
+		ID string
+	}{
+		ID: d.id,
+	})
+
+	d.SetErr(err)
+}
@@ -20,6 +20,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/murlokswarm/app"
 	"github.com/murlokswarm/app/internal/bridge"
@@ -71,6 +72,10 @@ type Driver struct {
 	// The URL of the component to load in the dock.
 	DockURL string
 
+	// The sanitization of the markup rendered by window components. Markup is
+	// not sanitized by default.
+	Sanitize app.SanitizeConfig
+
 	// The func called right after app.Run.
 	OnRun func()
 
@@ -105,6 +110,8 @@ type Driver struct {
 	menubar      *Menu
 	docktile     *DockTile
 	droppedFiles []string
+	storeOnce    sync.Once
+	store        app.Store
 }
 
 // Run satisfies the app.Driver interface.
@@ -142,6 +149,7 @@ func (d *Driver) Run(f *app.Factory) error {
 	d.goRPC.Handle("windows.OnDeminimize", handleWindow(onWindowDeminimize))
 	d.goRPC.Handle("windows.OnClose", handleWindow(onWindowClose))
 	d.goRPC.Handle("windows.OnCallback", handleWindow(onWindowCallback))
+	d.goRPC.Handle("windows.OnGoCall", handleWindow(onWindowGoCall))
 	d.goRPC.Handle("windows.OnNavigate", handleWindow(onWindowNavigate))
 	d.goRPC.Handle("windows.OnAlert", handleWindow(onWindowAlert))
 
@@ -229,6 +237,14 @@ func (d *Driver) Storage(path ...string) string {
 	return filepath.Join(d.support(), "storage", s)
 }
 
+// LocalStore satisfies the app.Driver interface.
+func (d *Driver) LocalStore() app.Store {
+	d.storeOnce.Do(func() {
+		d.store = core.NewFileStore(d.Storage("store"))
+	})
+	return d.store
+}
+
 // Render satisfies the app.Driver interface.
 func (d *Driver) Render(c app.Compo) {
 	e := d.ElemByCompo(c)
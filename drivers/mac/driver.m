@@ -70,6 +70,10 @@
           withHandler:^(id in, NSString *returnID) {
             return [Window render:in return:returnID];
           }];
+  [self.macRPC handle:@"windows.EvalJS"
+          withHandler:^(id in, NSString *returnID) {
+            return [Window evalJS:in return:returnID];
+          }];
   [self.macRPC handle:@"windows.Position"
           withHandler:^(id in, NSString *returnID) {
             return [Window position:in return:returnID];
@@ -84,25 +84,33 @@ func (m *Menu) Load(urlFmt string, v ...interface{}) {
 		return
 	}
 
+	if err = m.mount(c); err != nil {
+		return
+	}
+
+	if nav, ok := c.(app.Navigable); ok {
+		navURL, _ := url.Parse(u)
+		nav.OnNavigate(navURL)
+	}
+}
+
+// Mount satisfies the app.Menu interface.
+func (m *Menu) Mount(c app.Compo) {
+	m.SetErr(m.mount(c))
+}
+
+func (m *Menu) mount(c app.Compo) error {
 	m.compo = c
 
-	if err = driver.macRPC.Call("menus.Load", nil, struct {
+	if err := driver.macRPC.Call("menus.Load", nil, struct {
 		ID string
 	}{
 		ID: m.id,
 	}); err != nil {
-		return
-	}
-
-	err = m.dom.New(c)
-	if err != nil {
-		return
+		return err
 	}
 
-	if nav, ok := c.(app.Navigable); ok {
-		navURL, _ := url.Parse(u)
-		nav.OnNavigate(navURL)
-	}
+	return m.dom.New(c)
 }
 
 // Compo satisfies the app.Menu interface.
@@ -155,14 +163,16 @@ func onMenuCallback(m *Menu, in map[string]interface{}) interface{} {
 		return nil
 	}
 
-	var f func()
+	var f func() error
 	if f, err = mapping.Map(c); err != nil {
 		app.Logf("menu callback failed: %s", err)
 		return nil
 	}
 
 	if f != nil {
-		f()
+		if err = f(); err != nil {
+			app.Logf("menu callback failed: %+v", err)
+		}
 		return nil
 	}
 
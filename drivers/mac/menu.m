@@ -446,6 +446,11 @@
         [menu replaceChild:c];
         break;
 
+      case 9:
+      case 10:
+        // Styles are not supported in menus.
+        break;
+
       default:
         [NSException raise:@"ErrChange"
                     format:@"%@ change is not supported", action];
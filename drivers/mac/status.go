@@ -72,13 +72,17 @@ func (s *StatusMenu) Load(urlFmt string, v ...interface{}) {
 		return
 	}
 
-	err := driver.macRPC.Call("statusMenus.SetMenu", nil, struct {
-		ID string
-	}{
-		ID: s.id,
-	})
+	s.setMenu()
+}
 
-	s.SetErr(err)
+// Mount satisfies the app.StatusMenu interface.
+func (s *StatusMenu) Mount(c app.Compo) {
+	s.Menu.Mount(c)
+	if s.Err() != nil {
+		return
+	}
+
+	s.setMenu()
 }
 
 // SetIcon satisfies the app.StatusMenu interface.
@@ -123,3 +127,13 @@ func (s *StatusMenu) Close() {
 	s.SetErr(err)
 	driver.elems.Delete(s)
 }
+
+func (s *StatusMenu) setMenu() {
+	err := driver.macRPC.Call("statusMenus.SetMenu", nil, struct {
+		ID string
+	}{
+		ID: s.id,
+	})
+
+	s.SetErr(err)
+}
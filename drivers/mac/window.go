@@ -57,6 +57,8 @@ func newWindow(c app.WindowConfig) *Window {
 				dom.JsToGoHandler,
 				dom.HrefCompoFmt,
 			},
+			Encoding: dom.CompactEncoding,
+			Sanitize: driver.Sanitize,
 		},
 
 		onMove:           c.OnMove,
@@ -165,12 +167,28 @@ func (w *Window) Load(urlFmt string, v ...interface{}) {
 		return
 	}
 
-	w.compo = c
-
 	if u != w.history.Current() {
 		w.history.NewEntry(u)
 	}
 
+	if err = w.mount(c, u); err != nil {
+		return
+	}
+
+	if nav, ok := c.(app.Navigable); ok {
+		navURL, _ := url.Parse(u)
+		nav.OnNavigate(navURL)
+	}
+}
+
+// Mount satisfies the app.Window interface.
+func (w *Window) Mount(c app.Compo) {
+	w.SetErr(w.mount(c, app.CompoName(c)))
+}
+
+func (w *Window) mount(c app.Compo, u string) error {
+	w.compo = c
+
 	htmlConf := app.HTMLConfig{}
 	if configurator, ok := c.(app.Configurator); ok {
 		htmlConf = configurator.Config()
@@ -190,10 +208,11 @@ func (w *Window) Load(urlFmt string, v ...interface{}) {
 		CSS:           htmlConf.CSS,
 		Javascripts:   htmlConf.Javascripts,
 		GoRequest:     "window.webkit.messageHandlers.golangRequest.postMessage",
-		RootCompoName: n,
+		GoCall:        "window.webkit.messageHandlers.golangCall.postMessage",
+		RootCompoName: app.CompoName(c),
 	}
 
-	if err = driver.macRPC.Call("windows.Load", nil, struct {
+	if err := driver.macRPC.Call("windows.Load", nil, struct {
 		ID      string
 		Title   string
 		Page    string
@@ -206,18 +225,10 @@ func (w *Window) Load(urlFmt string, v ...interface{}) {
 		LoadURL: u,
 		BaseURL: driver.Resources(),
 	}); err != nil {
-		return
+		return err
 	}
 
-	err = w.dom.New(c)
-	if err != nil {
-		return
-	}
-
-	if nav, ok := c.(app.Navigable); ok {
-		navURL, _ := url.Parse(u)
-		nav.OnNavigate(navURL)
-	}
+	return w.dom.New(c)
 }
 
 // Compo satisfies the app.Window interface.
@@ -235,18 +246,62 @@ func (w *Window) Render(c app.Compo) {
 	w.SetErr(w.dom.Render(c))
 }
 
-func (w *Window) render(changes interface{}) error {
-	b, err := json.Marshal(changes)
+// CallNode satisfies the app.Window interface.
+func (w *Window) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	a, err := json.Marshal(args)
 	if err != nil {
-		return errors.Wrap(err, "encode changes failed")
+		return errors.Wrap(err, "encode node call args failed")
+	}
+
+	ret, err := w.evalJS(fmt.Sprintf("JSON.stringify(callNode(%s, %s, %s))",
+		jsString(nodeID),
+		jsString(method),
+		a,
+	))
+	if err != nil || out == nil || len(ret) == 0 {
+		return err
+	}
+
+	return json.Unmarshal([]byte(ret), out)
+}
+
+// EvalJS satisfies the app.JSEvaluator interface.
+func (w *Window) EvalJS(script string, out interface{}) error {
+	ret, err := w.evalJS(fmt.Sprintf("evalJS(%s)", jsString(script)))
+	if err != nil || out == nil || len(ret) == 0 {
+		return err
 	}
 
+	return json.Unmarshal([]byte(ret), out)
+}
+
+func (w *Window) evalJS(js string) (string, error) {
+	out := struct {
+		Result string
+	}{}
+
+	err := driver.macRPC.Call("windows.EvalJS", &out, struct {
+		ID string
+		JS string
+	}{
+		ID: w.id,
+		JS: js,
+	})
+
+	return out.Result, err
+}
+
+func (w *Window) render(changes interface{}) error {
 	return driver.macRPC.Call("windows.Render", nil, struct {
 		ID      string
 		Changes string
 	}{
 		ID:      w.id,
-		Changes: string(b),
+		Changes: string(changes.([]byte)),
 	})
 }
 
@@ -484,14 +539,16 @@ func onWindowCallback(w *Window, in map[string]interface{}) interface{} {
 		return nil
 	}
 
-	var f func()
+	var f func() error
 	if f, err = m.Map(c); err != nil {
 		app.Logf("window callback failed: %s", err)
 		return nil
 	}
 
 	if f != nil {
-		f()
+		if err = f(); err != nil {
+			app.Logf("window callback failed: %+v", err)
+		}
 		return nil
 	}
 
@@ -499,6 +556,36 @@ func onWindowCallback(w *Window, in map[string]interface{}) interface{} {
 	return nil
 }
 
+func onWindowGoCall(w *Window, in map[string]interface{}) interface{} {
+	var c struct {
+		ID      string
+		Name    string
+		JSONArg string
+	}
+
+	if err := json.Unmarshal([]byte(in["Call"].(string)), &c); err != nil {
+		app.Logf("window go call failed: %s", err)
+		return nil
+	}
+
+	ret, err := app.CallExposedFunc(c.Name, c.JSONArg)
+
+	errStr := ""
+	if err != nil {
+		errStr = err.Error()
+	}
+
+	if _, err = w.evalJS(fmt.Sprintf("returnGoFunc(%s, %s, %s)",
+		jsString(c.ID),
+		jsString(ret),
+		jsString(errStr),
+	)); err != nil {
+		app.Logf("window go call failed: %s", err)
+	}
+
+	return nil
+}
+
 func onWindowNavigate(w *Window, in map[string]interface{}) interface{} {
 	e := app.ElemByCompo(w.Compo())
 
@@ -606,6 +693,11 @@ func onWindowClose(w *Window, in map[string]interface{}) interface{} {
 	}
 }
 
+func jsString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
 func handleWindow(h func(w *Window, in map[string]interface{}) interface{}) bridge.GoRPCHandler {
 	return func(in map[string]interface{}) interface{} {
 		id, _ := in["ID"].(string)
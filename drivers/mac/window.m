@@ -95,6 +95,7 @@
   WKUserContentController *userContentController =
       [[WKUserContentController alloc] init];
   [userContentController addScriptMessageHandler:self name:@"golangRequest"];
+  [userContentController addScriptMessageHandler:self name:@"golangCall"];
 
   WKWebViewConfiguration *conf = [[WKWebViewConfiguration alloc] init];
   conf.userContentController = userContentController;
@@ -130,11 +131,21 @@
 
 - (void)userContentController:(WKUserContentController *)userContentController
       didReceiveScriptMessage:(WKScriptMessage *)message {
-  if (![message.name isEqual:@"golangRequest"]) {
+  Driver *driver = [Driver current];
+
+  if ([message.name isEqual:@"golangCall"]) {
+    NSDictionary *in = @{
+      @"ID" : self.ID,
+      @"Call" : message.body,
+    };
+
+    [driver.goRPC call:@"windows.OnGoCall" withInput:in onUI:YES];
     return;
   }
 
-  Driver *driver = [Driver current];
+  if (![message.name isEqual:@"golangRequest"]) {
+    return;
+  }
 
   NSDictionary *in = @{
     @"ID" : self.ID,
@@ -277,6 +288,35 @@
   });
 }
 
++ (void)evalJS:(NSDictionary *)in return:(NSString *)returnID {
+  defer(returnID, ^{
+    Driver *driver = [Driver current];
+    NSString *ID = in[@"ID"];
+
+    Window *win = driver.elements[ID];
+    if (win == nil) {
+      [NSException raise:@"ErrNoWindow" format:@"no window with id %@", ID];
+    }
+
+    [win.webview evaluateJavaScript:in[@"JS"]
+                  completionHandler:^(id result, NSError *error) {
+                    if (error != nil) {
+                      [driver.macRPC return:returnID
+                                 withOutput:nil
+                                   andError:error.localizedDescription];
+                      return;
+                    }
+
+                    NSString *res = [result isKindOfClass:[NSString class]]
+                                        ? result
+                                        : @"";
+                    [driver.macRPC return:returnID
+                               withOutput:@{@"Result" : res}
+                                 andError:nil];
+                  }];
+  });
+}
+
 + (void)position:(NSDictionary *)in return:(NSString *)returnID {
   defer(returnID, ^{
     Driver *driver = [Driver current];
@@ -59,6 +59,12 @@ func (m *Menu) Load(urlFmt string, v ...interface{}) {
 	err = m.dom.New(c)
 }
 
+// Mount satisfies the app.Menu interface.
+func (m *Menu) Mount(c app.Compo) {
+	m.compo = c
+	m.SetErr(m.dom.New(c))
+}
+
 // Compo satisfies the app.Menu interface.
 func (m *Menu) Compo() app.Compo {
 	return m.compo
@@ -71,6 +71,12 @@ func (p *Page) Load(urlFmt string, v ...interface{}) {
 	err = p.dom.New(c)
 }
 
+// Mount satisfies the app.Page interface.
+func (p *Page) Mount(c app.Compo) {
+	p.compo = c
+	p.SetErr(p.dom.New(c))
+}
+
 // Compo satisfies the app.Page interface.
 func (p *Page) Compo() app.Compo {
 	return p.compo
@@ -81,6 +81,12 @@ func (w *Window) Load(urlFmt string, v ...interface{}) {
 	err = w.dom.New(c)
 }
 
+// Mount satisfies the app.Window interface.
+func (w *Window) Mount(c app.Compo) {
+	w.compo = c
+	w.SetErr(w.dom.New(c))
+}
+
 // Compo satisfies the app.Window interface.
 func (w *Window) Compo() app.Compo {
 	return w.compo
@@ -0,0 +1,67 @@
+package web
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// assetsFile is the file generated by goapp web build that maps the resources
+// to their fingerprinted copy.
+const assetsFile = "goapp-assets.json"
+
+// assets maps resource paths relative to the resources directory to the path
+// of their fingerprinted copy.
+type assets struct {
+	names        map[string]string
+	fingerprints map[string]bool
+}
+
+func newAssets(data []byte) (assets, error) {
+	var a assets
+
+	if err := json.Unmarshal(data, &a.names); err != nil {
+		return a, err
+	}
+
+	a.fingerprints = make(map[string]bool, len(a.names))
+	for _, fingerprint := range a.names {
+		a.fingerprints[fingerprint] = true
+	}
+
+	return a, nil
+}
+
+// resolve returns the fingerprinted path of the given resource path. The
+// path is returned unchanged when the resource is not fingerprinted.
+func (a assets) resolve(p string) string {
+	prefix, name := splitResourcePath(p)
+
+	if fingerprint, ok := a.names[name]; ok {
+		return prefix + filepath.FromSlash(fingerprint)
+	}
+
+	return p
+}
+
+// isFingerprint reports whether the given resource path is a fingerprinted
+// copy.
+func (a assets) isFingerprint(p string) bool {
+	_, name := splitResourcePath(p)
+	return a.fingerprints[name]
+}
+
+// splitResourcePath splits the given path into the resources directory
+// prefix and the slash separated path within the resources directory.
+func splitResourcePath(p string) (prefix, name string) {
+	name = path.Clean(filepath.ToSlash(p))
+
+	for _, dir := range []string{"/resources/", "resources/"} {
+		if strings.HasPrefix(name, dir) {
+			return p[:len(dir)], name[len(dir):]
+		}
+	}
+
+	return "", strings.TrimPrefix(name, "/")
+}
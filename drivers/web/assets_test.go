@@ -0,0 +1,60 @@
+package web
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssets(t *testing.T) {
+	a, err := newAssets([]byte(`{
+		"css/main.css": "css/main.42.css",
+		"goapp.js": "goapp.21.js"
+	}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		path          string
+		resolved      string
+		isFingerprint bool
+	}{
+		{
+			path:     "resources/css/main.css",
+			resolved: "resources/css/main.42.css",
+		},
+		{
+			path:     "/resources/goapp.js",
+			resolved: "/resources/goapp.21.js",
+		},
+		{
+			path:     "goapp.js",
+			resolved: "goapp.21.js",
+		},
+		{
+			path:     "resources/css/other.css",
+			resolved: "resources/css/other.css",
+		},
+		{
+			path:          "resources/css/main.42.css",
+			resolved:      "resources/css/main.42.css",
+			isFingerprint: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.path, func(t *testing.T) {
+			assert.Equal(t, test.resolved, a.resolve(test.path))
+			assert.Equal(t, test.isFingerprint, a.isFingerprint(test.path))
+		})
+	}
+
+	_, err = newAssets([]byte(`{`))
+	assert.Error(t, err)
+}
+
+func TestAssetsEmpty(t *testing.T) {
+	var a assets
+	assert.Equal(t, "resources/goapp.js", a.resolve("resources/goapp.js"))
+	assert.False(t, a.isFingerprint("resources/goapp.js"))
+}
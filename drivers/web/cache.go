@@ -0,0 +1,49 @@
+// +build !js
+
+package web
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// cacheHandler sets the cache headers of the resources. Fingerprinted
+// resources never change and are cached for a year. Other resources are
+// revalidated with their ETag.
+type cacheHandler struct {
+	base   http.Handler
+	dir    string
+	assets assets
+}
+
+func newCacheHandler(h http.Handler, dir string, a assets) http.Handler {
+	return &cacheHandler{
+		base:   h,
+		dir:    dir,
+		assets: a,
+	}
+}
+
+func (h *cacheHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	name := path.Clean(strings.TrimPrefix(req.URL.Path, "/resources/"))
+
+	if h.assets.isFingerprint(name) {
+		res.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+		h.base.ServeHTTP(res, req)
+		return
+	}
+
+	res.Header().Set("Cache-Control", "no-cache")
+
+	// The tag is weak because the resources can be compressed.
+	info, err := os.Stat(filepath.Join(h.dir, filepath.FromSlash(name)))
+	if err == nil && !info.IsDir() {
+		res.Header().Set("ETag", fmt.Sprintf(`W/"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	}
+
+	h.base.ServeHTTP(res, req)
+}
@@ -0,0 +1,57 @@
+// +build js
+
+package web
+
+import (
+	"sync"
+)
+
+// callQueue is an unbounded queue of funcs that are called one at a time, in
+// the order they are pushed.
+type callQueue struct {
+	mutex  sync.Mutex
+	calls  []func()
+	signal chan struct{}
+}
+
+var callbacks = newCallQueue()
+
+func newCallQueue() *callQueue {
+	q := &callQueue{
+		signal: make(chan struct{}, 1),
+	}
+
+	go q.run()
+	return q
+}
+
+// push queues the given func. It never blocks.
+func (q *callQueue) push(f func()) {
+	q.mutex.Lock()
+	q.calls = append(q.calls, f)
+	q.mutex.Unlock()
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (q *callQueue) run() {
+	for range q.signal {
+		for {
+			q.mutex.Lock()
+			if len(q.calls) == 0 {
+				q.mutex.Unlock()
+				break
+			}
+
+			f := q.calls[0]
+			q.calls[0] = nil
+			q.calls = q.calls[1:]
+			q.mutex.Unlock()
+
+			f()
+		}
+	}
+}
@@ -5,9 +5,11 @@ package web
 
 import (
 	"context"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"strings"
 
-	"github.com/gopherjs/gopherjs/js"
 	"github.com/murlokswarm/app"
 	"github.com/murlokswarm/app/internal/core"
 	"github.com/murlokswarm/app/internal/logs"
@@ -48,8 +50,17 @@ func (d *Driver) Run(f *app.Factory) error {
 		}
 	}()
 
+	loadLocale()
+	d.loadAssets()
+
 	p := newPage(app.PageConfig{})
-	return p.Err()
+	if err := p.Err(); err != nil {
+		return err
+	}
+
+	watchHotReload(p)
+	keepAlive()
+	return nil
 }
 
 // AppName satisfies the app.Driver interface.
@@ -57,13 +68,20 @@ func (d *Driver) AppName() string {
 	return "go webapp"
 }
 
-// Storage satisfies the app.Driver interface.
+// Storage satisfies the app.Driver interface. Web clients do not have a file
+// system: data is persisted with LocalStore.
 func (d *Driver) Storage(p ...string) string {
 	return ""
 }
 
+// LocalStore satisfies the app.Driver interface. Values are stored in the
+// browser local storage and blobs in IndexedDB.
+func (d *Driver) LocalStore() app.Store {
+	return browserStore
+}
+
 func (d *Driver) NewPage(c app.PageConfig) app.Page {
-	js.Global.Get("location").Set("href", c.URL)
+	jsGlobal().Get("location").Set("href", c.URL)
 	return d.Driver.NewPage(c)
 }
 
@@ -84,3 +102,102 @@ func (d *Driver) ElemByCompo(c app.Compo) app.Elem {
 func (d *Driver) CallOnUIGoroutine(f func()) {
 	d.uichan <- f
 }
+
+// loadLocale sets the app locale to the browser language and loads its
+// catalogs from the server resources.
+// Catalogs are fetched in the background in order to not delay the first
+// render. Components are rendered again in the new locale once they arrive.
+func loadLocale() {
+	app.SetLocale(jsGlobal().Get("navigator").Get("language").String())
+
+	locale := app.Locale()
+	locales := []string{locale}
+
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		locales = append(locales, locale[:i])
+	}
+
+	go loadCatalogs(locales)
+}
+
+func loadCatalogs(locales []string) {
+	for _, l := range locales {
+		res, err := http.Get("/resources/locales/" + l + ".json")
+		if err != nil {
+			app.Logf("loading %s catalog failed: %s", l, err)
+			continue
+		}
+
+		data, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		if err != nil || res.StatusCode != http.StatusOK {
+			continue
+		}
+
+		if err = app.LoadCatalog(l, data); err != nil {
+			app.Logf("loading %s catalog failed: %s", l, err)
+		}
+	}
+}
+
+// loadAssets loads the fingerprinted resources generated by goapp web build
+// from the server.
+func (d *Driver) loadAssets() {
+	res, err := http.Get("/resources/" + assetsFile)
+	if err != nil {
+		app.Logf("loading fingerprinted resources failed: %s", err)
+		return
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return
+	}
+
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		app.Logf("loading fingerprinted resources failed: %s", err)
+		return
+	}
+
+	if d.assets, err = newAssets(data); err != nil {
+		app.Logf("loading fingerprinted resources failed: %s", err)
+	}
+}
+
+// watchHotReload reloads the page when the server is restarted by goapp web
+// run -w. The state of the page components is kept in the session storage
+// and restored once the page is reloaded.
+func watchHotReload(p app.Page) {
+	meta := jsGlobal().Get("document").Call("querySelector", `meta[name="`+string(hotReloadMeta)+`"]`)
+	if meta.IsNull() {
+		return
+	}
+
+	serverID := ""
+	events := jsGlobal().Get("EventSource").New(meta.Call("getAttribute", "content"))
+
+	events.Set("onmessage", jsCallback(func(args []jsValue) {
+		id := args[0].Get("data").String()
+
+		if len(serverID) == 0 {
+			serverID = id
+			return
+		}
+
+		if id == serverID {
+			return
+		}
+
+		events.Call("close")
+
+		driver.CallOnUIGoroutine(func() {
+			if page, ok := p.(*Page); ok {
+				page.snapshot()
+			}
+
+			jsGlobal().Get("location").Call("reload")
+		})
+	}))
+}
@@ -0,0 +1,172 @@
+// +build !js
+
+package web
+
+import (
+	"compress/gzip"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// compressedEncodings are the encodings of the precompressed resources
+// created by goapp web build, by order of preference.
+var compressedEncodings = []struct {
+	name string
+	ext  string
+}{
+	{name: "br", ext: ".br"},
+	{name: "gzip", ext: ".gz"},
+}
+
+// uncompressibleExts are the extensions of the resources that are already
+// compressed.
+var uncompressibleExts = map[string]bool{
+	".br":    true,
+	".gif":   true,
+	".gz":    true,
+	".ico":   true,
+	".jpeg":  true,
+	".jpg":   true,
+	".mp3":   true,
+	".mp4":   true,
+	".ogg":   true,
+	".otf":   true,
+	".png":   true,
+	".ttf":   true,
+	".webm":  true,
+	".webp":  true,
+	".woff":  true,
+	".woff2": true,
+	".zip":   true,
+}
+
+type gzipResponseWritter struct {
+	res  http.ResponseWriter
+	gzip *gzip.Writer
+}
+
+func newGzipWriter(w http.ResponseWriter) *gzipResponseWritter {
+	w.Header().Set("Content-Encoding", "gzip")
+
+	return &gzipResponseWritter{
+		res:  w,
+		gzip: gzip.NewWriter(w),
+	}
+}
+
+func (w *gzipResponseWritter) Header() http.Header {
+	return w.res.Header()
+}
+
+func (w *gzipResponseWritter) Write(b []byte) (int, error) {
+	return w.gzip.Write(b)
+}
+
+func (w *gzipResponseWritter) WriteHeader(statusCode int) {
+	w.res.WriteHeader(statusCode)
+}
+
+func (w *gzipResponseWritter) Close() error {
+	return w.gzip.Close()
+}
+
+// compressHandler serves the precompressed version of the resources that
+// matches the encodings accepted by the browser. Resources that are not
+// precompressed are compressed on the fly with gzip, unless they are already
+// compressed.
+type compressHandler struct {
+	base http.Handler
+	dir  string
+}
+
+func newCompressHandler(h http.Handler, dir string) http.Handler {
+	return &compressHandler{
+		base: h,
+		dir:  dir,
+	}
+}
+
+func (h *compressHandler) ServeHTTP(res http.ResponseWriter, req *http.Request) {
+	name := path.Clean(strings.TrimPrefix(req.URL.Path, "/resources/"))
+
+	if uncompressibleExts[path.Ext(name)] {
+		h.base.ServeHTTP(res, req)
+		return
+	}
+
+	res.Header().Add("Vary", "Accept-Encoding")
+	accept := req.Header.Get("Accept-Encoding")
+	filename := filepath.Join(h.dir, filepath.FromSlash(name))
+
+	for _, enc := range compressedEncodings {
+		if !acceptsEncoding(accept, enc.name) {
+			continue
+		}
+
+		if h.serveCompressed(res, req, filename, enc.name, enc.ext) {
+			return
+		}
+	}
+
+	if !acceptsEncoding(accept, "gzip") {
+		h.base.ServeHTTP(res, req)
+		return
+	}
+
+	w := newGzipWriter(res)
+	defer w.Close()
+
+	res = w
+	h.base.ServeHTTP(res, req)
+}
+
+// serveCompressed serves the compressed version of the given file. It
+// reports whether the compressed version exists.
+func (h *compressHandler) serveCompressed(res http.ResponseWriter, req *http.Request, filename, encoding, ext string) bool {
+	f, err := os.Open(filename + ext)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	res.Header().Set("Content-Encoding", encoding)
+	http.ServeContent(res, req, filepath.Base(filename), info.ModTime(), f)
+	return true
+}
+
+// acceptsEncoding reports whether the given Accept-Encoding header value
+// accepts the given encoding.
+func acceptsEncoding(accept, encoding string) bool {
+	for _, v := range strings.Split(accept, ",") {
+		params := strings.Split(v, ";")
+
+		if name := strings.TrimSpace(params[0]); name != encoding && name != "*" {
+			continue
+		}
+
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+
+			if !strings.HasPrefix(p, "q=") {
+				continue
+			}
+
+			if q, err := strconv.ParseFloat(p[2:], 64); err == nil && q == 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return false
+}
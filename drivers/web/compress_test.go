@@ -0,0 +1,131 @@
+// +build !js
+
+package web
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcceptsEncoding(t *testing.T) {
+	tests := []struct {
+		accept   string
+		encoding string
+		expected bool
+	}{
+		{accept: "gzip, deflate, br", encoding: "br", expected: true},
+		{accept: "gzip, deflate, br", encoding: "gzip", expected: true},
+		{accept: "gzip;q=1.0, br;q=0.5", encoding: "br", expected: true},
+		{accept: "gzip, br;q=0", encoding: "br", expected: false},
+		{accept: "*", encoding: "br", expected: true},
+		{accept: "deflate", encoding: "gzip", expected: false},
+		{accept: "", encoding: "gzip", expected: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accept+" "+test.encoding, func(t *testing.T) {
+			assert.Equal(t, test.expected, acceptsEncoding(test.accept, test.encoding))
+		})
+	}
+}
+
+func TestCompressHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-compress")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+
+	write("main.js", "console.log('hello')")
+	write("main.js.br", "brotli")
+	write("main.js.gz", "gzip")
+	write("main.css", "body {}")
+	write("logo.png", "png")
+
+	handler := http.StripPrefix("/resources/", http.FileServer(http.Dir(dir)))
+	handler = newCompressHandler(handler, dir)
+
+	tests := []struct {
+		scenario        string
+		path            string
+		accept          string
+		contentEncoding string
+		contentType     string
+		body            string
+	}{
+		{
+			scenario:        "brotli",
+			path:            "/resources/main.js",
+			accept:          "gzip, deflate, br",
+			contentEncoding: "br",
+			contentType:     "javascript",
+			body:            "brotli",
+		},
+		{
+			scenario:        "precompressed gzip",
+			path:            "/resources/main.js",
+			accept:          "gzip, deflate",
+			contentEncoding: "gzip",
+			contentType:     "javascript",
+			body:            "gzip",
+		},
+		{
+			scenario:    "no compression",
+			path:        "/resources/main.js",
+			contentType: "javascript",
+			body:        "console.log('hello')",
+		},
+		{
+			scenario:        "gzip on the fly",
+			path:            "/resources/main.css",
+			accept:          "gzip, br",
+			contentEncoding: "gzip",
+			contentType:     "css",
+			body:            "body {}",
+		},
+		{
+			scenario:    "already compressed",
+			path:        "/resources/logo.png",
+			accept:      "gzip, br",
+			contentType: "image/png",
+			body:        "png",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, test.path, nil)
+			req.Header.Set("Accept-Encoding", test.accept)
+
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, req)
+
+			assert.Equal(t, http.StatusOK, res.Code)
+			assert.Equal(t, test.contentEncoding, res.Header().Get("Content-Encoding"))
+			assert.Contains(t, res.Header().Get("Content-Type"), test.contentType)
+
+			body := res.Body.Bytes()
+
+			if test.scenario == "gzip on the fly" {
+				r, err := gzip.NewReader(bytes.NewReader(body))
+				require.NoError(t, err)
+
+				body, err = ioutil.ReadAll(r)
+				require.NoError(t, err)
+			}
+
+			assert.Equal(t, test.body, string(body))
+		})
+	}
+}
@@ -1,9 +1,11 @@
 // Package web is the driver to be used for web applications.
-// It is build on the top of GopherJS.
+// Its client is built with GopherJS or in WebAssembly.
 package web
 
 import (
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/murlokswarm/app"
 	"github.com/murlokswarm/app/internal/core"
@@ -22,10 +24,46 @@ type Driver struct {
 
 	// The server used to save request.
 	// Default is a server that listens on port 7042.
+	// Its handler is set by the driver.
 	Server *http.Server
 
+	// The TLS certificate and key files. The server uses HTTPS and HTTP/2
+	// when they are set.
+	CertFile string
+	KeyFile  string
+
+	// The address of the server that redirects HTTP requests to HTTPS
+	// (eg. :80). Only applied when the server uses TLS.
+	RedirectAddr string
+
+	// The max age of the Strict-Transport-Security header that is set when
+	// the server uses TLS. Default is one year. A negative value disables the
+	// header.
+	HSTSMaxAge time.Duration
+
+	// The Content-Security-Policy header of the pages. No header is set when
+	// it is empty. AutoContentSecurityPolicy sets a policy derived from the
+	// page scripts and styles.
+	ContentSecurityPolicy string
+
+	// The middlewares that wrap the server routes, including pages and
+	// resources. The first middleware is the outermost one.
+	Middlewares []Middleware
+
+	// The app handlers, keyed by pattern like with http.ServeMux. A pattern
+	// that ends with a slash mounts a handler under a prefix (eg. /api/).
+	// Patterns must not collide with the driver routes and component names.
+	Routes map[string]http.Handler
+
+	// The sanitization of the markup rendered by components. Markup is not
+	// sanitized by default.
+	Sanitize app.SanitizeConfig
+
+	// The web app manifest configuration.
+	// Only applied when the app is build with goapp web build.
+	Manifest Manifest
+
 	// OnServerRun is called when the web server is running.
-	// http.Handler overrides should be performed here.
 	OnServerRun func()
 
 	factory     *app.Factory
@@ -33,10 +71,32 @@ type Driver struct {
 	page        app.Page
 	uichan      chan func()
 	stop        func()
-	fileHandler http.Handler
+	hotReloadID string
+	pwa         bool
+	assets      assets
+	mutex       sync.Mutex
+	redirect    *http.Server
 }
 
+// Middleware is a function that wraps an http.Handler. It is used to add
+// behaviors such as authentication, logging or CORS to the server routes.
+type Middleware func(http.Handler) http.Handler
+
+// hotReloadPath is the path of the event stream that tells browsers to reload
+// when the server is restarted by goapp web run -w.
+const hotReloadPath = "/goapp/hotreload"
+
+// hotReloadMeta is the name of the meta that contains the hot reload event
+// stream path. It is set only when hot reload is enabled.
+const hotReloadMeta app.MetaName = "goapp-hot-reload"
+
 // Name satisfies the app.Driver interface.
 func (d *Driver) Name() string {
 	return "Web"
 }
+
+// Resources satisfies the app.Driver interface. Resources that are
+// fingerprinted by goapp web build are resolved to their fingerprinted copy.
+func (d *Driver) Resources(p ...string) string {
+	return d.assets.resolve(d.Driver.Resources(p...))
+}
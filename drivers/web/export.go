@@ -0,0 +1,200 @@
+// +build !js
+
+package web
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/murlokswarm/app"
+	"github.com/murlokswarm/app/internal/core"
+	"github.com/murlokswarm/app/internal/dom"
+	"github.com/murlokswarm/app/internal/file"
+	"github.com/pkg/errors"
+)
+
+const exportLoadTimeout = time.Minute
+
+// export renders the pages of the given routes on the server side and writes
+// them with the resources in the given directory. Static components are
+// exported when no route is given.
+//
+// Pages of components that report being static are written without the
+// client runtime when strip is true. Components that implement app.Loader are
+// exported once their content is loaded.
+func (d *Driver) export(dir string, routes []string, strip bool) error {
+	if len(routes) == 0 {
+		routes = d.staticRoutes()
+	}
+
+	for _, route := range routes {
+		if err := d.exportPage(dir, route, strip); err != nil {
+			return errors.Wrapf(err, "exporting %s failed", route)
+		}
+
+		app.Logf("%s exported", route)
+	}
+
+	if err := file.Sync(filepath.Join(dir, "resources"), d.Resources()); err != nil {
+		return err
+	}
+
+	if !d.pwa {
+		return nil
+	}
+
+	return file.Copy(
+		filepath.Join(dir, serviceWorkerFile),
+		d.Driver.Resources(serviceWorkerFile),
+	)
+}
+
+// staticRoutes returns the routes of the registered components that
+// implement app.Static.
+func (d *Driver) staticRoutes() []string {
+	var routes []string
+
+	if isStaticCompo(d.factory, d.URL) {
+		routes = append(routes, "/")
+	}
+
+	for _, name := range d.factory.CompoNames() {
+		if isStaticCompo(d.factory, name) {
+			routes = append(routes, "/"+name)
+		}
+	}
+
+	return routes
+}
+
+func isStaticCompo(f *app.Factory, rawurl string) bool {
+	u, err := url.Parse(rawurl)
+	if err != nil || len(rawurl) == 0 {
+		return false
+	}
+
+	c, err := f.NewCompo(core.CompoNameFromURL(u))
+	if err != nil {
+		return false
+	}
+
+	_, ok := c.(app.Static)
+	return ok
+}
+
+func (d *Driver) exportPage(dir, route string, strip bool) error {
+	u, err := url.Parse(route)
+	if err != nil {
+		return err
+	}
+
+	path := strings.Trim(u.Path, "/")
+
+	if len(path) == 0 {
+		if u, err = url.Parse(d.URL); err != nil {
+			return err
+		}
+	}
+
+	compoName := core.CompoNameFromURL(u)
+
+	c, err := d.factory.NewCompo(compoName)
+	if err != nil {
+		return err
+	}
+
+	ui := make(chan func(), 64)
+
+	e := &dom.Engine{
+		Factory:        d.factory,
+		Resources:      d.Resources,
+		AttrTransforms: []dom.Transform{dom.JsToGoHandler},
+		Sanitize:       d.Sanitize,
+		CallOnUIGoroutine: func(f func()) {
+			ui <- f
+		},
+	}
+	defer e.Close()
+
+	if err = e.New(c); err != nil {
+		return err
+	}
+
+	if nav, ok := c.(app.Navigable); ok {
+		nav.OnNavigate(u)
+
+		if err = e.Render(c); err != nil {
+			return err
+		}
+	}
+
+	if err = waitLoads(e, ui); err != nil {
+		return err
+	}
+
+	page := d.newPage(compoName, c)
+	page.Body = e.HTML()
+	page.Styles = e.Styles()
+
+	if static, ok := c.(app.Static); ok && strip && static.Static() {
+		page.Static = true
+		page.Javascripts = removeFilename(page.Javascripts, "goapp.js")
+	}
+
+	page.CSS = absolutePaths(page.CSS)
+	page.Javascripts = absolutePaths(page.Javascripts)
+
+	filename := filepath.Join(dir, filepath.FromSlash(path), "index.html")
+
+	if err = os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, []byte(page.String()), 0644)
+}
+
+// waitLoads applies the asynchronous loads of the engine components until
+// none is pending. Loaded components can mount other loaders.
+func waitLoads(e *dom.Engine, ui chan func()) error {
+	timeout := time.After(exportLoadTimeout)
+
+	for e.Loading() {
+		select {
+		case f := <-ui:
+			f()
+
+		case <-timeout:
+			return errors.New("loading components timed out")
+		}
+	}
+
+	return nil
+}
+
+// absolutePaths makes the given resource paths relative to the website root
+// in order to be loaded from any exported page.
+func absolutePaths(paths []string) []string {
+	for i, p := range paths {
+		if !strings.HasPrefix(p, "/") && !strings.Contains(p, "://") {
+			paths[i] = "/" + p
+		}
+	}
+
+	return paths
+}
+
+func removeFilename(paths []string, name string) []string {
+	res := paths[:0]
+
+	for _, p := range paths {
+		if filepath.Base(p) != name {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,58 @@
+// +build !js
+
+package web
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Loaded struct {
+	Content string
+}
+
+func (l *Loaded) Load(ctx context.Context) (func(), error) {
+	return func() {
+		l.Content = "loaded content"
+	}, nil
+}
+
+func (l *Loaded) Render() string {
+	return `
+	<div>
+		{{if loading}}
+			<p>loading</p>
+		{{else}}
+			<p>{{.Content}}</p>
+		{{end}}
+	</div>
+	`
+}
+
+func TestDriverExportLoader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-export")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	f := app.NewFactory()
+	f.RegisterCompo(&Loaded{})
+
+	d := &Driver{
+		URL:     "/web.loaded",
+		factory: f,
+	}
+
+	require.NoError(t, d.exportPage(dir, "/web.loaded", false))
+
+	page, err := ioutil.ReadFile(filepath.Join(dir, "web.loaded", "index.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "loaded content")
+	assert.NotContains(t, string(page), "<p>loading</p>")
+}
@@ -0,0 +1,98 @@
+// +build js,!wasm
+
+package web
+
+import (
+	"github.com/gopherjs/gopherjs/js"
+)
+
+// jsValue is a javascript value. It hides the differences between the
+// GopherJS and the WebAssembly clients.
+type jsValue struct {
+	object *js.Object
+}
+
+// jsCallback is a Go func that can be called from javascript. Calls are
+// queued and performed one at a time, in the order of the javascript events,
+// outside of the javascript callback so they can block.
+type jsCallback func(args []jsValue)
+
+// jsSyncCallback is a Go func that is called from javascript before the
+// javascript caller returns. It must not block.
+type jsSyncCallback func(args []jsValue)
+
+func jsGlobal() jsValue {
+	return jsValue{object: js.Global}
+}
+
+func (v jsValue) Get(name string) jsValue {
+	return jsValue{object: v.object.Get(name)}
+}
+
+func (v jsValue) Set(name string, x interface{}) {
+	v.object.Set(name, jsArg(x))
+}
+
+func (v jsValue) Call(name string, args ...interface{}) jsValue {
+	return jsValue{object: v.object.Call(name, jsArgs(args)...)}
+}
+
+func (v jsValue) New(args ...interface{}) jsValue {
+	return jsValue{object: v.object.New(jsArgs(args)...)}
+}
+
+func (v jsValue) String() string {
+	return v.object.String()
+}
+
+// Bytes returns the content of a Uint8Array.
+func (v jsValue) Bytes() []byte {
+	return v.object.Interface().([]byte)
+}
+
+// IsNull reports whether the value is null or undefined.
+func (v jsValue) IsNull() bool {
+	return v.object == nil || v.object == js.Undefined
+}
+
+func jsArgs(args []interface{}) []interface{} {
+	for i, a := range args {
+		args[i] = jsArg(a)
+	}
+	return args
+}
+
+func jsArg(x interface{}) interface{} {
+	switch x := x.(type) {
+	case jsValue:
+		return x.object
+
+	case jsCallback:
+		return func(args ...*js.Object) {
+			values := jsValues(args)
+			callbacks.push(func() { x(values) })
+		}
+
+	case jsSyncCallback:
+		return func(args ...*js.Object) {
+			x(jsValues(args))
+		}
+
+	default:
+		// GopherJS converts []byte to Uint8Array.
+		return x
+	}
+}
+
+func jsValues(args []*js.Object) []jsValue {
+	values := make([]jsValue, len(args))
+	for i, a := range args {
+		values[i] = jsValue{object: a}
+	}
+	return values
+}
+
+// keepAlive blocks while the client is running. GopherJS clients keep
+// running after the main func returns.
+func keepAlive() {
+}
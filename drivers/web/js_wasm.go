@@ -0,0 +1,168 @@
+// +build js,wasm
+
+package web
+
+import (
+	"encoding/json"
+	"sync"
+	"syscall/js"
+)
+
+// jsValue is a javascript value. It hides the differences between the
+// GopherJS and the WebAssembly clients.
+type jsValue struct {
+	value js.Value
+}
+
+// jsCallback is a Go func that can be called from javascript. Calls are
+// queued and performed one at a time, in the order of the javascript events.
+type jsCallback func(args []jsValue)
+
+// jsSyncCallback is a Go func that is called from javascript before the
+// javascript caller returns. It must not block.
+type jsSyncCallback func(args []jsValue)
+
+func jsGlobal() jsValue {
+	return jsValue{value: js.Global()}
+}
+
+func (v jsValue) Get(name string) jsValue {
+	return jsValue{value: v.value.Get(name)}
+}
+
+// Set sets the named property. The func value of a callback that is replaced
+// is released.
+func (v jsValue) Set(name string, x interface{}) {
+	arg := jsArg(x)
+	funcs.release(v.value, name)
+	v.value.Set(name, arg)
+
+	if f, ok := arg.(js.Func); ok {
+		funcs.keep(v.value, name, f)
+	}
+}
+
+func (v jsValue) Call(name string, args ...interface{}) jsValue {
+	return jsValue{value: v.value.Call(name, jsArgs(args)...)}
+}
+
+func (v jsValue) New(args ...interface{}) jsValue {
+	return jsValue{value: v.value.New(jsArgs(args)...)}
+}
+
+func (v jsValue) String() string {
+	return v.value.String()
+}
+
+// Bytes returns the content of a Uint8Array.
+func (v jsValue) Bytes() []byte {
+	b := make([]byte, v.value.Get("length").Int())
+	js.CopyBytesToGo(b, v.value)
+	return b
+}
+
+// IsNull reports whether the value is null or undefined.
+func (v jsValue) IsNull() bool {
+	return v.value.IsNull() || v.value.IsUndefined()
+}
+
+func jsArgs(args []interface{}) []interface{} {
+	for i, a := range args {
+		args[i] = jsArg(a)
+	}
+	return args
+}
+
+func jsArg(x interface{}) interface{} {
+	switch x := x.(type) {
+	case nil, bool, string, int, float64:
+		return x
+
+	case jsValue:
+		return x.value
+
+	case jsCallback:
+		return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			values := make([]jsValue, len(args))
+			for i, a := range args {
+				values[i] = jsValue{value: a}
+			}
+
+			// Callbacks must not block the javascript event loop.
+			callbacks.push(func() { x(values) })
+			return nil
+		})
+
+	case jsSyncCallback:
+		return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			values := make([]jsValue, len(args))
+			for i, a := range args {
+				values[i] = jsValue{value: a}
+			}
+
+			x(values)
+			return nil
+		})
+
+	case []byte:
+		a := js.Global().Get("Uint8Array").New(len(x))
+		js.CopyBytesToJS(a, x)
+		return a
+
+	default:
+		// Other values are converted like GopherJS does with structs, maps
+		// and slices.
+		b, err := json.Marshal(x)
+		if err != nil {
+			return js.Undefined()
+		}
+		return js.Global().Get("JSON").Call("parse", string(b))
+	}
+}
+
+// funcRegistry keeps the func values set as object properties so they can be
+// released when they are replaced. Objects reference their func values by id
+// in a property prefixed by funcProperty.
+type funcRegistry struct {
+	mutex  sync.Mutex
+	funcs  map[int]js.Func
+	lastID int
+}
+
+const funcProperty = "goappFunc:"
+
+var funcs = &funcRegistry{
+	funcs: make(map[int]js.Func),
+}
+
+func (r *funcRegistry) keep(obj js.Value, name string, f js.Func) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastID++
+	r.funcs[r.lastID] = f
+	obj.Set(funcProperty+name, r.lastID)
+}
+
+func (r *funcRegistry) release(obj js.Value, name string) {
+	id := obj.Get(funcProperty + name)
+	if id.Type() != js.TypeNumber {
+		return
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if f, ok := r.funcs[id.Int()]; ok {
+		f.Release()
+		delete(r.funcs, id.Int())
+	}
+
+	obj.Delete(funcProperty + name)
+}
+
+// keepAlive blocks while the client is running. WebAssembly clients stop
+// when the main func returns.
+func keepAlive() {
+	select {}
+}
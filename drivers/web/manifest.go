@@ -0,0 +1,38 @@
+package web
+
+// Manifest is the struct that describes the web app manifest.
+// It is used to make the app installable and usable offline.
+// Only applied when the app is build with goapp web build.
+type Manifest struct {
+	// The app name. Default is the package name.
+	Name string
+
+	// The name displayed when there is not enough space to display the app
+	// name. Default is the app name.
+	ShortName string
+
+	// The app icon path relative to the resources directory as .png file.
+	// Provide a big one! Other required icon sizes will be auto generated.
+	Icon string
+
+	// The color of the browser user interface (eg. #1e1e1e).
+	ThemeColor string
+
+	// The color of the splash screen displayed when the app is launched.
+	BackgroundColor string
+
+	// The display mode: fullscreen, standalone, minimal-ui or browser.
+	// Default is standalone.
+	Display string
+
+	// The URL that is loaded when the app is launched. Default is /.
+	StartURL string
+}
+
+// The paths of the files generated by goapp web build to make the app
+// installable and usable offline.
+const (
+	manifestFile      = "manifest.webmanifest"
+	serviceWorkerFile = "goapp-sw.js"
+	serviceWorkerPath = "/" + serviceWorkerFile
+)
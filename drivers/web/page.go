@@ -8,7 +8,6 @@ import (
 	"net/url"
 
 	"github.com/google/uuid"
-	"github.com/gopherjs/gopherjs/js"
 	"github.com/murlokswarm/app"
 	"github.com/murlokswarm/app/internal/core"
 	"github.com/murlokswarm/app/internal/dom"
@@ -32,6 +31,8 @@ func newPage(c app.PageConfig) app.Page {
 			Factory:        driver.factory,
 			Resources:      driver.Resources,
 			AttrTransforms: []dom.Transform{dom.JsToGoHandler},
+			Encoding:       dom.BinaryEncoding,
+			Sanitize:       driver.Sanitize,
 		},
 	}
 
@@ -39,11 +40,20 @@ func newPage(c app.PageConfig) app.Page {
 
 	driver.elems.Put(p)
 
-	js.Global.Set("golangRequest", p.onPageRequest)
-	js.Global.Call("addEventListener", "unload", p.onClose)
+	jsGlobal().Set("golangRequest", jsCallback(func(args []jsValue) {
+		p.onPageRequest(args[0].String())
+	}))
+
+	jsGlobal().Set("golangCall", jsCallback(func(args []jsValue) {
+		p.onGoCall(args[0].String())
+	}))
+
+	jsGlobal().Call("addEventListener", "unload", jsCallback(func(args []jsValue) {
+		p.onClose()
+	}))
 
 	u := p.URL()
-	u.Path = js.Global.Get("loadedComp").String()
+	u.Path = jsGlobal().Get("loadedComp").String()
 	p.Load(u.String())
 	return p
 }
@@ -83,21 +93,29 @@ func (p *Page) Load(urlFmt string, v ...interface{}) {
 	n := core.CompoNameFromURL(u)
 
 	var c app.Compo
-	if c, err = driver.factory.NewCompo(n); err != nil {
-		return
+	if c = p.restoreSnapshot(); c == nil {
+		if c, err = driver.factory.NewCompo(n); err != nil {
+			return
+		}
+
+		if err = p.dom.New(c); err != nil {
+			return
+		}
 	}
 
 	p.compo = c
 
-	if err = p.dom.New(c); err != nil {
-		return
-	}
-
 	if nav, ok := c.(app.Navigable); ok {
 		nav.OnNavigate(u)
 	}
 }
 
+// Mount satisfies the app.Page interface.
+func (p *Page) Mount(c app.Compo) {
+	p.compo = c
+	p.SetErr(p.dom.New(c))
+}
+
 func (p *Page) Compo() app.Compo {
 	return p.compo
 }
@@ -110,19 +128,107 @@ func (p *Page) Render(c app.Compo) {
 	p.SetErr(p.dom.Render(c))
 }
 
-func (p *Page) render(changes interface{}) error {
-	b, err := json.Marshal(changes)
+// CallNode satisfies the app.Page interface.
+func (p *Page) CallNode(nodeID, method string, out interface{}, args ...interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("calling %s on node %s failed: %v", method, nodeID, r)
+		}
+	}()
+
+	if args == nil {
+		args = []interface{}{}
+	}
+
+	ret := jsGlobal().Call("callNode", nodeID, method, args)
+	if out == nil || ret.IsNull() {
+		return nil
+	}
+
+	b := jsGlobal().Get("JSON").Call("stringify", ret).String()
+	return json.Unmarshal([]byte(b), out)
+}
+
+// EvalJS satisfies the app.JSEvaluator interface.
+func (p *Page) EvalJS(script string, out interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("evaluating javascript failed: %v", r)
+		}
+	}()
+
+	ret := jsGlobal().Call("evalJS", script).String()
+	if out == nil {
+		return nil
+	}
+
+	return json.Unmarshal([]byte(ret), out)
+}
+
+// snapshotKey is the session storage key where the state of the page
+// components is kept during a hot reload.
+const snapshotKey = "goapp.snapshot"
+
+type pageSnapshot struct {
+	URL   string
+	State json.RawMessage
+}
+
+// snapshot saves the state of the page components in the session storage.
+func (p *Page) snapshot() {
+	state, err := p.dom.Snapshot()
 	if err != nil {
-		return errors.Wrap(err, "marshal changes failed")
+		app.Logf("snapshotting page failed: %s", err)
+		return
 	}
 
-	c := js.Global.Get("JSON").Call("parse", string(b))
-	js.Global.Call("render", c)
+	s, err := json.Marshal(pageSnapshot{
+		URL:   p.currentURL,
+		State: state,
+	})
+	if err != nil {
+		app.Logf("snapshotting page failed: %s", err)
+		return
+	}
+
+	jsGlobal().Get("sessionStorage").Call("setItem", snapshotKey, string(s))
+}
+
+// restoreSnapshot restores the page components when their state has been
+// saved before a hot reload of the current url. It returns the root
+// component or nil when there is nothing to restore.
+func (p *Page) restoreSnapshot() app.Compo {
+	storage := jsGlobal().Get("sessionStorage")
+
+	item := storage.Call("getItem", snapshotKey)
+	if item.IsNull() {
+		return nil
+	}
+	storage.Call("removeItem", snapshotKey)
+
+	var s pageSnapshot
+	if err := json.Unmarshal([]byte(item.String()), &s); err != nil || s.URL != p.currentURL {
+		return nil
+	}
+
+	c, err := p.dom.Restore(s.State)
+	if err != nil {
+		app.Logf("restoring page failed: %s", err)
+		return nil
+	}
+
+	return c
+}
+
+func (p *Page) render(changes interface{}) error {
+	// Changes are binary encoded. They are passed to javascript as an
+	// Uint8Array.
+	jsGlobal().Call("render", changes.([]byte))
 	return nil
 }
 
 func (p *Page) Reload() {
-	js.Global.Get("location").Call("reload")
+	jsGlobal().Get("location").Call("reload")
 }
 
 func (p *Page) CanPrevious() bool {
@@ -130,7 +236,7 @@ func (p *Page) CanPrevious() bool {
 }
 
 func (p *Page) Previous() {
-	js.Global.Get("history").Call("back")
+	jsGlobal().Get("history").Call("back")
 }
 
 func (p *Page) CanNext() bool {
@@ -138,12 +244,11 @@ func (p *Page) CanNext() bool {
 }
 
 func (p *Page) Next() {
-	js.Global.Get("history").Call("forward")
+	jsGlobal().Get("history").Call("forward")
 }
 
 func (p *Page) URL() *url.URL {
-	u, _ := url.Parse(js.
-		Global.
+	u, _ := url.Parse(jsGlobal().
 		Get("location").
 		Get("href").
 		String(),
@@ -152,8 +257,7 @@ func (p *Page) URL() *url.URL {
 }
 
 func (p *Page) Referer() *url.URL {
-	u, _ := url.Parse(js.
-		Global.
+	u, _ := url.Parse(jsGlobal().
 		Get("document").
 		Get("referrer").
 		String(),
@@ -162,7 +266,7 @@ func (p *Page) Referer() *url.URL {
 }
 
 func (p *Page) Close() {
-	js.Global.Call("close")
+	jsGlobal().Call("close")
 }
 
 func (p *Page) onPageRequest(mappingStr string) {
@@ -178,20 +282,46 @@ func (p *Page) onPageRequest(mappingStr string) {
 		return
 	}
 
-	var f func()
+	var f func() error
 	if f, err = m.Map(c); err != nil {
 		app.Logf("page callback failed: %s", err)
 		return
 	}
 
 	if f != nil {
-		f()
+		if err = f(); err != nil {
+			app.Logf("page callback failed: %+v", err)
+		}
 		return
 	}
 
 	app.Render(c)
 }
 
+func (p *Page) onGoCall(callStr string) {
+	var c struct {
+		ID      string
+		Name    string
+		JSONArg string
+	}
+
+	if err := json.Unmarshal([]byte(callStr), &c); err != nil {
+		app.Logf("go call failed: %s", err)
+		return
+	}
+
+	go driver.CallOnUIGoroutine(func() {
+		ret, err := app.CallExposedFunc(c.Name, c.JSONArg)
+
+		errStr := ""
+		if err != nil {
+			errStr = err.Error()
+		}
+
+		jsGlobal().Call("returnGoFunc", c.ID, ret, errStr)
+	})
+}
+
 func (p *Page) onClose() {
 	driver.elems.Delete(p)
 }
@@ -0,0 +1,162 @@
+// +build !js
+
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/murlokswarm/app/internal/dom"
+)
+
+// defaultHSTSMaxAge is the default max age of the Strict-Transport-Security
+// header.
+const defaultHSTSMaxAge = time.Hour * 24 * 365
+
+func (d *Driver) tls() bool {
+	return len(d.CertFile) != 0 && len(d.KeyFile) != 0
+}
+
+// securityHeaders is the middleware that sets the security headers of the
+// server responses.
+func (d *Driver) securityHeaders(h http.Handler) http.Handler {
+	maxAge := d.HSTSMaxAge
+	if maxAge == 0 {
+		maxAge = defaultHSTSMaxAge
+	}
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		header := res.Header()
+		header.Set("X-Content-Type-Options", "nosniff")
+		header.Set("X-Frame-Options", "SAMEORIGIN")
+		header.Set("Referrer-Policy", "strict-origin-when-cross-origin")
+
+		if d.tls() && maxAge > 0 {
+			header.Set("Strict-Transport-Security", fmt.Sprintf(
+				"max-age=%d; includeSubDomains",
+				int64(maxAge/time.Second),
+			))
+		}
+
+		h.ServeHTTP(res, req)
+	})
+}
+
+// AutoContentSecurityPolicy is the Content-Security-Policy that is derived
+// from the scripts and styles of each page. Scripts are allowed from the page
+// origin, from the hosts of the page scripts and from the hashes of the
+// inline scripts. Styles are also allowed inline since components are styled
+// by the client runtime.
+//
+// Other resources are restricted to the page origin, except images and fonts
+// that can also be loaded with https. Requests to other origins, like fetch
+// or XMLHttpRequest ones, are blocked: apps that make them should set their
+// own policy.
+const AutoContentSecurityPolicy = "auto"
+
+// contentSecurityPolicy returns the Content-Security-Policy of the given
+// page. It returns an empty string when no policy is set.
+func (d *Driver) contentSecurityPolicy(page dom.Page, html string) string {
+	if d.ContentSecurityPolicy != AutoContentSecurityPolicy {
+		return d.ContentSecurityPolicy
+	}
+
+	// The client runtime evaluates the javascript handlers and the
+	// WebAssembly client.
+	scripts := append([]string{"'self'", "'unsafe-eval'"}, resourceHosts(page.Javascripts)...)
+	scripts = append(scripts, inlineScriptHashes(html)...)
+
+	styles := append([]string{"'self'", "'unsafe-inline'"}, resourceHosts(page.CSS)...)
+
+	return strings.Join([]string{
+		"default-src 'self'",
+		"img-src 'self' data: https:",
+		"font-src 'self' data: https:",
+		"style-src " + strings.Join(styles, " "),
+		"script-src " + strings.Join(scripts, " "),
+	}, "; ")
+}
+
+// resourceHosts returns the origins of the given resource urls that are not
+// served by the driver.
+func resourceHosts(paths []string) []string {
+	origins := make(map[string]struct{})
+
+	for _, p := range paths {
+		u, err := url.Parse(p)
+		if err != nil || len(u.Host) == 0 {
+			continue
+		}
+
+		scheme := u.Scheme
+		if len(scheme) == 0 {
+			scheme = "https"
+		}
+
+		origins[scheme+"://"+u.Host] = struct{}{}
+	}
+
+	hosts := make([]string, 0, len(origins))
+	for o := range origins {
+		hosts = append(hosts, o)
+	}
+
+	sort.Strings(hosts)
+	return hosts
+}
+
+// inlineScriptHashes returns the CSP hash sources of the inline scripts of
+// the given markup.
+func inlineScriptHashes(html string) []string {
+	var hashes []string
+
+	for {
+		start := strings.Index(html, "<script>")
+		if start < 0 {
+			return hashes
+		}
+		html = html[start+len("<script>"):]
+
+		end := strings.Index(html, "</script>")
+		if end < 0 {
+			return hashes
+		}
+
+		sum := sha256.Sum256([]byte(html[:end]))
+		hashes = append(hashes, "'sha256-"+base64.StdEncoding.EncodeToString(sum[:])+"'")
+		html = html[end+len("</script>"):]
+	}
+}
+
+// redirectHandler returns a handler that redirects requests to the HTTPS
+// server that listens on the given address.
+func redirectHandler(tlsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(tlsAddr)
+
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		host, _, err := net.SplitHostPort(req.Host)
+		if err != nil {
+			host = req.Host
+		}
+
+		if len(port) != 0 && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+
+		u := url.URL{
+			Scheme:   "https",
+			Host:     host,
+			Path:     req.URL.Path,
+			RawQuery: req.URL.RawQuery,
+		}
+
+		http.Redirect(res, req, u.String(), http.StatusMovedPermanently)
+	})
+}
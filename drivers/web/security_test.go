@@ -0,0 +1,252 @@
+// +build !js
+
+package web
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/murlokswarm/app"
+	"github.com/murlokswarm/app/internal/dom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	tests := []struct {
+		scenario string
+		driver   *Driver
+		hsts     string
+	}{
+		{
+			scenario: "without tls",
+			driver:   &Driver{},
+		},
+		{
+			scenario: "with tls",
+			driver:   &Driver{CertFile: "cert.pem", KeyFile: "key.pem"},
+			hsts:     "max-age=31536000; includeSubDomains",
+		},
+		{
+			scenario: "with tls and max age",
+			driver:   &Driver{CertFile: "cert.pem", KeyFile: "key.pem", HSTSMaxAge: time.Hour},
+			hsts:     "max-age=3600; includeSubDomains",
+		},
+		{
+			scenario: "with tls and hsts disabled",
+			driver:   &Driver{CertFile: "cert.pem", KeyFile: "key.pem", HSTSMaxAge: -1},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			h := test.driver.securityHeaders(http.NotFoundHandler())
+
+			res := httptest.NewRecorder()
+			h.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+
+			assert.Equal(t, "nosniff", res.Header().Get("X-Content-Type-Options"))
+			assert.Equal(t, test.hsts, res.Header().Get("Strict-Transport-Security"))
+		})
+	}
+}
+
+func TestContentSecurityPolicy(t *testing.T) {
+	page := dom.Page{
+		CSS: []string{
+			"resources/css/main.css",
+			"https://fonts.googleapis.com/css?family=Roboto",
+		},
+		Javascripts: []string{
+			"resources/goapp.js",
+			"//cdn.example.com/lib.js",
+		},
+	}
+	html := `<script>alert("hello")</script><script src="resources/goapp.js"></script>`
+
+	d := &Driver{}
+	assert.Empty(t, d.contentSecurityPolicy(page, html))
+
+	d.ContentSecurityPolicy = AutoContentSecurityPolicy
+	csp := d.contentSecurityPolicy(page, html)
+
+	sum := sha256.Sum256([]byte(`alert("hello")`))
+	hash := base64.StdEncoding.EncodeToString(sum[:])
+
+	assert.Contains(t, csp, "default-src 'self'")
+	assert.Contains(t, csp, "style-src 'self' 'unsafe-inline' https://fonts.googleapis.com;")
+	assert.Contains(t, csp, "script-src 'self' 'unsafe-eval' https://cdn.example.com 'sha256-"+hash+"'")
+
+	d.ContentSecurityPolicy = "default-src *"
+	assert.Equal(t, "default-src *", d.contentSecurityPolicy(page, html))
+}
+
+type Clickable struct {
+	Value string
+}
+
+func (c *Clickable) Render() string {
+	return `
+	<div>
+		<button onclick="Click">click</button>
+		<input onchange="Value">
+		<p onmouseover="js:console.log(event)">hello</p>
+	</div>`
+}
+
+func (c *Clickable) Click() {}
+
+func TestContentSecurityPolicyHandlers(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Clickable{})
+
+	d := &Driver{
+		URL:                   "/web.clickable",
+		ContentSecurityPolicy: AutoContentSecurityPolicy,
+		factory:               f,
+	}
+
+	handler, err := d.newHandler()
+	require.NoError(t, err)
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, res.Code)
+
+	csp := res.Header().Get("Content-Security-Policy")
+	require.NotEmpty(t, csp)
+
+	var scripts string
+	for _, directive := range strings.Split(csp, ";") {
+		if directive = strings.TrimSpace(directive); strings.HasPrefix(directive, "script-src ") {
+			scripts = directive
+		}
+	}
+	require.NotEmpty(t, scripts)
+
+	// Inline handlers are blocked by the policy: the client runtime binds
+	// the handlers emitted by the engine with addEventListener and evaluates
+	// the javascript ones.
+	assert.NotContains(t, scripts, "'unsafe-inline'")
+	assert.Contains(t, scripts, "'unsafe-eval'")
+	assert.NotContains(t, res.Body.String(), "onclick=")
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		tlsAddr  string
+		url      string
+		location string
+	}{
+		{
+			tlsAddr:  ":443",
+			url:      "http://example.com/hello?name=world",
+			location: "https://example.com/hello?name=world",
+		},
+		{
+			tlsAddr:  ":7043",
+			url:      "http://localhost:7042/hello",
+			location: "https://localhost:7043/hello",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.url, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			redirectHandler(test.tlsAddr).ServeHTTP(res, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+			assert.Equal(t, http.StatusMovedPermanently, res.Code)
+			assert.Equal(t, test.location, res.Header().Get("Location"))
+		})
+	}
+}
+
+func TestDriverStopRedirect(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-tls")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	d := &Driver{
+		Server:       &http.Server{Addr: freeAddr(t)},
+		CertFile:     filepath.Join(dir, "cert.pem"),
+		KeyFile:      filepath.Join(dir, "key.pem"),
+		RedirectAddr: freeAddr(t),
+	}
+	writeTestCert(t, d.CertFile, d.KeyFile)
+
+	errC := make(chan error, 1)
+	go func() {
+		errC <- d.serve()
+	}()
+
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	var res *http.Response
+	for i := 0; i < 100; i++ {
+		if res, err = client.Get("http://" + d.RedirectAddr + "/hello"); err == nil {
+			break
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	require.NoError(t, err)
+	res.Body.Close()
+	assert.Equal(t, http.StatusMovedPermanently, res.StatusCode)
+
+	d.Stop()
+	require.NoError(t, <-errC)
+
+	_, err = net.Dial("tcp", d.RedirectAddr)
+	assert.Error(t, err)
+}
+
+func freeAddr(t *testing.T) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return l.Addr().String()
+}
+
+func writeTestCert(t *testing.T, certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	tmpl := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"localhost"},
+	}
+
+	cert, err := x509.CreateCertificate(rand.Reader, &tmpl, &tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600)
+	require.NoError(t, err)
+
+	err = ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+	require.NoError(t, err)
+}
@@ -4,16 +4,23 @@ package web
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/murlokswarm/app"
 	"github.com/murlokswarm/app/internal/core"
 	"github.com/murlokswarm/app/internal/dom"
 	"github.com/murlokswarm/app/internal/file"
 	"github.com/murlokswarm/app/internal/logs"
+	"github.com/pkg/errors"
 )
 
 func init() {
@@ -30,12 +37,33 @@ func init() {
 
 // Run satisfies the app.Driver interface.
 func (d *Driver) Run(f *app.Factory) error {
+	if manifest := os.Getenv("GOAPP_MANIFEST"); len(manifest) != 0 {
+		return d.runGoappManifest(manifest)
+	}
+
 	d.factory = f
 
 	if len(d.NotFoundURL) == 0 {
 		d.NotFoundURL = "/web.NotFound"
 	}
 
+	if _, err := os.Stat(d.Driver.Resources(serviceWorkerFile)); err == nil {
+		d.pwa = true
+	}
+
+	if err := d.loadAssets(); err != nil {
+		return errors.Wrap(err, "loading fingerprinted resources failed")
+	}
+
+	if dir := os.Getenv("GOAPP_EXPORT"); len(dir) != 0 {
+		var routes []string
+		if r := os.Getenv("GOAPP_EXPORT_ROUTES"); len(r) != 0 {
+			routes = strings.Split(r, ",")
+		}
+
+		return d.export(dir, routes, len(os.Getenv("GOAPP_EXPORT_STRIP")) != 0)
+	}
+
 	if d.Server == nil {
 		d.Server = &http.Server{
 			Addr: ":7042",
@@ -46,29 +74,132 @@ func (d *Driver) Run(f *app.Factory) error {
 		d.Server.Addr = addr
 	}
 
-	http.Handle("/", d)
+	if cert := os.Getenv("GOAPP_TLS_CERT"); len(cert) != 0 {
+		d.CertFile = cert
+		d.KeyFile = os.Getenv("GOAPP_TLS_KEY")
+	}
 
-	fileHandler := http.FileServer(http.Dir("resources"))
-	fileHandler = http.StripPrefix("/resources/", fileHandler)
-	fileHandler = newGzipHandler(fileHandler)
-	http.Handle("/resources/", fileHandler)
+	if addr := os.Getenv("GOAPP_REDIRECT_ADDR"); len(addr) != 0 {
+		d.RedirectAddr = addr
+	}
+
+	if maxAge := os.Getenv("GOAPP_HSTS_MAX_AGE"); len(maxAge) != 0 {
+		var err error
+		if d.HSTSMaxAge, err = time.ParseDuration(maxAge); err != nil {
+			return errors.Wrap(err, "parsing GOAPP_HSTS_MAX_AGE failed")
+		}
+	}
+
+	if len(os.Getenv("GOAPP_HOT_RELOAD")) != 0 {
+		d.hotReloadID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+
+	handler, err := d.newHandler()
+	if err != nil {
+		return err
+	}
+	d.Server.Handler = handler
 
 	if d.OnServerRun != nil {
 		d.OnServerRun()
 	}
 
-	errC := make(chan error)
-	go func() {
-		err := d.Server.ListenAndServe()
-		if err == http.ErrServerClosed {
-			err = nil
+	return d.serve()
+}
+
+// serve runs the server and the HTTPS redirect server until one of them
+// fails or Stop is called.
+func (d *Driver) serve() error {
+	errC := make(chan error, 2)
+
+	if d.tls() && len(d.RedirectAddr) != 0 {
+		redirect := &http.Server{
+			Addr:    d.RedirectAddr,
+			Handler: redirectHandler(d.Server.Addr),
 		}
-		errC <- err
+
+		d.mutex.Lock()
+		d.redirect = redirect
+		d.mutex.Unlock()
+
+		go func() {
+			err := redirect.ListenAndServe()
+			if err == http.ErrServerClosed {
+				err = nil
+			}
+			errC <- err
+		}()
+	}
+
+	go func() {
+		errC <- d.listen()
 	}()
 
 	return <-errC
 }
 
+// listen serves the requests with TLS and HTTP/2 when a certificate is set.
+// HTTP/2 is enabled by the http package when the server uses TLS.
+func (d *Driver) listen() error {
+	var err error
+
+	if d.tls() {
+		err = d.Server.ListenAndServeTLS(d.CertFile, d.KeyFile)
+	} else {
+		err = d.Server.ListenAndServe()
+	}
+
+	if err == http.ErrServerClosed {
+		err = nil
+	}
+
+	return err
+}
+
+// newHandler returns the handler that serves the driver and app routes,
+// wrapped by the middlewares.
+func (d *Driver) newHandler() (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.Handle("/", d)
+
+	fileHandler := http.FileServer(http.Dir("resources"))
+	fileHandler = http.StripPrefix("/resources/", fileHandler)
+	fileHandler = newCompressHandler(fileHandler, "resources")
+	fileHandler = newCacheHandler(fileHandler, "resources", d.assets)
+	mux.Handle("/resources/", fileHandler)
+	mux.HandleFunc(serviceWorkerPath, d.serveServiceWorker)
+
+	if len(d.hotReloadID) != 0 {
+		mux.HandleFunc(hotReloadPath, d.serveHotReload)
+	}
+
+	for pattern, h := range d.Routes {
+		if isDriverRoute(pattern) {
+			return nil, errors.Errorf("route %s is reserved by the driver", pattern)
+		}
+
+		mux.Handle(pattern, h)
+	}
+
+	handler := d.securityHeaders(mux)
+
+	for i := len(d.Middlewares) - 1; i >= 0; i-- {
+		handler = d.Middlewares[i](handler)
+	}
+
+	return handler, nil
+}
+
+func isDriverRoute(pattern string) bool {
+	switch pattern {
+	case "/", "/resources/", serviceWorkerPath, hotReloadPath:
+		return true
+
+	default:
+		return false
+	}
+}
+
 // ServeHTTP is the http.Handler that route wether to serve a page or a
 // resource.
 func (d *Driver) ServeHTTP(res http.ResponseWriter, req *http.Request) {
@@ -98,32 +229,145 @@ func (d *Driver) handle(res http.ResponseWriter, req *http.Request, status int)
 		return
 	}
 
+	page := d.newPage(compoName, c)
+	html := page.String()
+
+	if csp := d.contentSecurityPolicy(page, html); len(csp) != 0 {
+		res.Header().Set("Content-Security-Policy", csp)
+	}
+
+	res.WriteHeader(status)
+	res.Write([]byte(html))
+}
+
+// newPage returns the page that loads the named component.
+func (d *Driver) newPage(compoName string, c app.Compo) dom.Page {
 	htmlConf := app.HTMLConfig{}
 	if configurator, ok := c.(app.Configurator); ok {
 		htmlConf = configurator.Config()
 	}
 
 	if len(htmlConf.CSS) == 0 {
-		htmlConf.CSS = file.Filenames(d.Resources("css"), ".css")
+		htmlConf.CSS = d.resourceFilenames("css", ".css")
 	}
 
 	if len(htmlConf.Javascripts) == 0 {
-		htmlConf.Javascripts = file.Filenames(d.Resources("js"), ".js")
+		htmlConf.Javascripts = d.resourceFilenames("js", ".js")
 	}
 
+	htmlConf.CSS = d.resolveAssets(htmlConf.CSS)
+	htmlConf.Javascripts = d.resolveAssets(htmlConf.Javascripts)
 	htmlConf.Javascripts = append(htmlConf.Javascripts, d.Resources("goapp.js"))
 
+	if len(d.Manifest.ThemeColor) != 0 {
+		htmlConf.Metas = append(htmlConf.Metas, app.Meta{
+			Name:    app.ThemeColorMeta,
+			Content: d.Manifest.ThemeColor,
+		})
+	}
+
+	if len(d.hotReloadID) != 0 {
+		htmlConf.Metas = append(htmlConf.Metas, app.Meta{
+			Name:    hotReloadMeta,
+			Content: hotReloadPath,
+		})
+	}
+
 	page := dom.Page{
 		Title:         htmlConf.Title,
 		Metas:         htmlConf.Metas,
 		CSS:           cleanWindowsPath(htmlConf.CSS),
 		Javascripts:   cleanWindowsPath(htmlConf.Javascripts),
 		GoRequest:     "console.log", // Overloaded in client.go.
+		GoCall:        "console.log", // Overloaded in client.go.
 		RootCompoName: compoName,
 	}
 
-	res.WriteHeader(status)
-	res.Write([]byte(page.String()))
+	if d.pwa {
+		page.Manifest = "/resources/" + manifestFile
+
+		// The service worker caches the resources, which would prevent hot
+		// reload to get the rebuilt ones.
+		if len(d.hotReloadID) == 0 {
+			page.ServiceWorker = serviceWorkerPath
+		}
+	}
+
+	return page
+}
+
+// resourceFilenames returns the files with the given extension in the given
+// resources directory. Fingerprinted copies are excluded.
+func (d *Driver) resourceFilenames(dir, ext string) []string {
+	var filenames []string
+
+	for _, f := range file.Filenames(d.Driver.Resources(dir), ext) {
+		if !d.assets.isFingerprint(f) {
+			filenames = append(filenames, f)
+		}
+	}
+
+	return filenames
+}
+
+func (d *Driver) resolveAssets(paths []string) []string {
+	resolved := make([]string, 0, len(paths))
+
+	for _, p := range paths {
+		resolved = append(resolved, d.assets.resolve(p))
+	}
+
+	return resolved
+}
+
+// loadAssets loads the fingerprinted resources generated by goapp web build.
+func (d *Driver) loadAssets() error {
+	data, err := ioutil.ReadFile(filepath.Join("resources", assetsFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.assets, err = newAssets(data)
+	return err
+}
+
+// serveServiceWorker serves the service worker generated by goapp web build.
+// It is served from the website root in order to control all the app pages.
+func (d *Driver) serveServiceWorker(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Cache-Control", "no-cache")
+	http.ServeFile(res, req, d.Driver.Resources(serviceWorkerFile))
+}
+
+// runGoappManifest writes the manifest configuration in the given file. It
+// is used by goapp web build to generate the web app manifest.
+func (d *Driver) runGoappManifest(filename string) error {
+	b, err := json.MarshalIndent(d.Manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, b, 0644)
+}
+
+// serveHotReload sends the server id to the browsers. Browsers reconnect when
+// the server restarts and reload the page when the id changes.
+func (d *Driver) serveHotReload(res http.ResponseWriter, req *http.Request) {
+	flusher, ok := res.(http.Flusher)
+	if !ok {
+		http.Error(res, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	res.Header().Set("Content-Type", "text/event-stream")
+	res.Header().Set("Cache-Control", "no-cache")
+
+	fmt.Fprintf(res, "retry: 500\ndata: %s\n\n", d.hotReloadID)
+	flusher.Flush()
+
+	<-req.Context().Done()
 }
 
 // AppName satisfies the app.Driver interface.
@@ -143,6 +387,14 @@ func (d *Driver) CallOnUIGoroutine(f func()) {
 
 // Stop shutdown the server.
 func (d *Driver) Stop() {
+	d.mutex.Lock()
+	redirect := d.redirect
+	d.mutex.Unlock()
+
+	if redirect != nil {
+		redirect.Shutdown(context.Background())
+	}
+
 	d.Server.Shutdown(context.Background())
 }
 
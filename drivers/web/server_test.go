@@ -0,0 +1,150 @@
+// +build !js
+
+package web
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Hello struct {
+	Name string
+}
+
+func (h *Hello) Render() string {
+	return `<h1>hello</h1>`
+}
+
+func header(name, value string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+			res.Header().Add(name, value)
+			h.ServeHTTP(res, req)
+		})
+	}
+}
+
+func TestDriverHandler(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Hello{})
+	f.RegisterCompo(&NotFound{})
+
+	d := &Driver{
+		URL:         "/web.hello",
+		NotFoundURL: "/web.NotFound",
+		factory:     f,
+		Middlewares: []Middleware{
+			header("X-Order", "first"),
+			header("X-Order", "second"),
+		},
+		Routes: map[string]http.Handler{
+			"/api/": http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				res.Write([]byte("api " + req.URL.Path))
+			}),
+		},
+	}
+
+	handler, err := d.newHandler()
+	require.NoError(t, err)
+
+	tests := []struct {
+		scenario string
+		path     string
+		status   int
+		body     string
+	}{
+		{
+			scenario: "root page",
+			path:     "/",
+			status:   http.StatusOK,
+			body:     "web.hello",
+		},
+		{
+			scenario: "page",
+			path:     "/web.hello",
+			status:   http.StatusOK,
+			body:     "web.hello",
+		},
+		{
+			scenario: "not found page",
+			path:     "/web.unknown",
+			status:   http.StatusNotFound,
+			body:     "web.notfound",
+		},
+		{
+			scenario: "app route",
+			path:     "/api/users",
+			status:   http.StatusOK,
+			body:     "api /api/users",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			res := httptest.NewRecorder()
+			handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+			assert.Equal(t, test.status, res.Code)
+			assert.Contains(t, res.Body.String(), test.body)
+			assert.Equal(t, []string{"first", "second"}, res.Header()["X-Order"])
+		})
+	}
+}
+
+func TestDriverHandlerReservedRoute(t *testing.T) {
+	for _, pattern := range []string{"/", "/resources/", serviceWorkerPath, hotReloadPath} {
+		t.Run(pattern, func(t *testing.T) {
+			d := &Driver{
+				Routes: map[string]http.Handler{
+					pattern: http.NotFoundHandler(),
+				},
+			}
+
+			_, err := d.newHandler()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCacheHandler(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-cache")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.css"), []byte("body {}"), 0644))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "main.42.css"), []byte("body {}"), 0644))
+
+	a, err := newAssets([]byte(`{"main.css": "main.42.css"}`))
+	require.NoError(t, err)
+
+	handler := http.StripPrefix("/resources/", http.FileServer(http.Dir(dir)))
+	handler = newCacheHandler(handler, dir, a)
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/resources/main.42.css", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "public, max-age=31536000, immutable", res.Header().Get("Cache-Control"))
+	assert.Empty(t, res.Header().Get("ETag"))
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/resources/main.css", nil))
+	assert.Equal(t, http.StatusOK, res.Code)
+	assert.Equal(t, "no-cache", res.Header().Get("Cache-Control"))
+
+	etag := res.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/resources/main.css", nil)
+	req.Header.Set("If-None-Match", etag)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, req)
+	assert.Equal(t, http.StatusNotModified, res.Code)
+}
@@ -0,0 +1,193 @@
+// +build js
+
+package web
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+)
+
+// browserStore is the store of the web clients.
+var browserStore = &storage{}
+
+// The local storage keys are prefixed in order to not collide with the keys
+// set by other scripts.
+const localStoragePrefix = "goapp."
+
+// The IndexedDB database and object store where blobs are stored.
+const (
+	blobDatabase = "goapp"
+	blobStore    = "blobs"
+)
+
+// storage is an app.Store implementation that stores values in the browser
+// local storage and blobs in IndexedDB.
+// Blob operations wait for the IndexedDB requests to complete. Event handlers
+// can call them since javascript callbacks are performed outside of the
+// javascript event loop.
+type storage struct {
+	once  sync.Once
+	db    jsValue
+	dbErr error
+}
+
+// Get satisfies the app.Store interface.
+func (s *storage) Get(key string, v interface{}) error {
+	var item jsValue
+
+	if err := jsCatch(func() {
+		item = localStorage().Call("getItem", localStoragePrefix+key)
+	}); err != nil {
+		return err
+	}
+
+	if item.IsNull() {
+		return app.ErrNotFound
+	}
+
+	return json.Unmarshal([]byte(item.String()), v)
+}
+
+// Set satisfies the app.Store interface.
+func (s *storage) Set(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return jsCatch(func() {
+		localStorage().Call("setItem", localStoragePrefix+key, string(b))
+	})
+}
+
+// Del satisfies the app.Store interface.
+func (s *storage) Del(key string) error {
+	return jsCatch(func() {
+		localStorage().Call("removeItem", localStoragePrefix+key)
+	})
+}
+
+// GetBlob satisfies the app.Store interface.
+func (s *storage) GetBlob(key string) ([]byte, error) {
+	res, err := s.request("readonly", func(store jsValue) jsValue {
+		return store.Call("get", key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if res.IsNull() {
+		return nil, app.ErrNotFound
+	}
+
+	return res.Bytes(), nil
+}
+
+// SetBlob satisfies the app.Store interface.
+func (s *storage) SetBlob(key string, b []byte) error {
+	_, err := s.request("readwrite", func(store jsValue) jsValue {
+		return store.Call("put", b, key)
+	})
+	return err
+}
+
+// DelBlob satisfies the app.Store interface.
+func (s *storage) DelBlob(key string) error {
+	_, err := s.request("readwrite", func(store jsValue) jsValue {
+		return store.Call("delete", key)
+	})
+	return err
+}
+
+// request performs the request created by the given func on the blob store
+// and returns its result.
+func (s *storage) request(mode string, fn func(store jsValue) jsValue) (jsValue, error) {
+	s.once.Do(s.open)
+
+	if s.dbErr != nil {
+		return jsValue{}, s.dbErr
+	}
+
+	var req jsValue
+
+	if err := jsCatch(func() {
+		tx := s.db.Call("transaction", blobStore, mode)
+		req = fn(tx.Call("objectStore", blobStore))
+	}); err != nil {
+		return jsValue{}, err
+	}
+
+	if err := waitRequest(req); err != nil {
+		return jsValue{}, err
+	}
+
+	return req.Get("result"), nil
+}
+
+func (s *storage) open() {
+	var req jsValue
+
+	if s.dbErr = jsCatch(func() {
+		req = jsGlobal().Get("indexedDB").Call("open", blobDatabase, 1)
+	}); s.dbErr != nil {
+		return
+	}
+
+	// The object store can only be created while the upgrade event is
+	// dispatched.
+	req.Set("onupgradeneeded", jsSyncCallback(func(args []jsValue) {
+		req.Get("result").Call("createObjectStore", blobStore)
+	}))
+
+	s.dbErr = waitRequest(req)
+	req.Set("onupgradeneeded", nil)
+
+	if s.dbErr != nil {
+		s.dbErr = errors.Wrap(s.dbErr, "opening blob store failed")
+		return
+	}
+
+	s.db = req.Get("result")
+}
+
+// waitRequest waits for the given IndexedDB request to complete. The request
+// callbacks are synchronous since the waiting goroutine may be the one that
+// performs the queued callbacks.
+func waitRequest(req jsValue) error {
+	done := make(chan error, 1)
+
+	req.Set("onsuccess", jsSyncCallback(func(args []jsValue) {
+		done <- nil
+	}))
+
+	req.Set("onerror", jsSyncCallback(func(args []jsValue) {
+		done <- errors.Errorf("indexeddb request failed: %s", req.Get("error").String())
+	}))
+
+	err := <-done
+
+	// Replacing the callbacks releases them on WebAssembly.
+	req.Set("onsuccess", nil)
+	req.Set("onerror", nil)
+	return err
+}
+
+func localStorage() jsValue {
+	return jsGlobal().Get("localStorage")
+}
+
+// jsCatch calls the given func and returns the javascript exception it
+// throws as an error.
+func jsCatch(f func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("%v", r)
+		}
+	}()
+
+	f()
+	return nil
+}
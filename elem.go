@@ -43,6 +43,13 @@ type ElemWithCompo interface {
 	// It returns an error if the component is not imported.
 	Load(url string, v ...interface{})
 
+	// Mount mounts the given component as the element root component.
+	// It allows a component to display a part of its content in another
+	// element, like a panel or a menu: the caller keeps a reference to the
+	// mounted component and can modify and render it. Events that occur in the
+	// element are delivered to the mounted component.
+	Mount(c Compo)
+
 	// Compo returns the loaded component.
 	Compo() Compo
 
@@ -51,6 +58,12 @@ type ElemWithCompo interface {
 
 	// Render renders the component.
 	Render(Compo)
+
+	// CallNode calls the named method on the node with the given identifier
+	// and stores the result in the value pointed by out.
+	// It returns ErrNotSupported when the element does not support node
+	// manipulations.
+	CallNode(nodeID, method string, out interface{}, args ...interface{}) error
 }
 
 // Navigator is the interface that describes an element that supports
@@ -74,6 +87,19 @@ type Navigator interface {
 	Next()
 }
 
+// JSEvaluator is the interface that describes an element that can evaluate
+// javascript. Windows and pages implement it:
+//
+//	if w, ok := app.ElemByCompo(c).(app.JSEvaluator); ok {
+//	    err := w.EvalJS("document.title", &title)
+//	}
+type JSEvaluator interface {
+	// EvalJS evaluates the given javascript and stores the JSON encoded result
+	// in the value pointed by out.
+	// It returns ErrNotSupported when the element can't evaluate javascript.
+	EvalJS(script string, out interface{}) error
+}
+
 // Closer is the interface that describes an element that can be closed.
 type Closer interface {
 	// Close closes the element and free its allocated resources.
@@ -160,6 +160,17 @@ type WheelEvent struct {
 	Source    EventSource
 }
 
+// ScrollEvent represents an onscroll event arg.
+type ScrollEvent struct {
+	ScrollTop    float64
+	ScrollLeft   float64
+	ScrollHeight float64
+	ScrollWidth  float64
+	ClientHeight float64
+	ClientWidth  float64
+	Source       EventSource
+}
+
 // DeltaMode is an indication of the units of measurement for a delta value.
 type DeltaMode uint64
 
@@ -2,6 +2,7 @@ package app
 
 import (
 	"reflect"
+	"sort"
 	"sync"
 
 	"github.com/pkg/errors"
@@ -42,7 +43,52 @@ func (f *Factory) RegisterCompo(c Compo) (name string, err error) {
 	name = CompoName(c)
 	f.types[name] = v.Type()
 	return name, nil
+}
+
+// Lint checks the templates of all the registered components, including that
+// the components they use are registered.
+// It returns a LintErrors that describes the mistakes found.
+//
+// Components are rendered without holding the factory lock, so their Render
+// method can safely use the factory.
+func (f *Factory) Lint() error {
+	f.mutex.Lock()
+	types := make(map[string]reflect.Type, len(f.types))
+	for name, t := range f.types {
+		types[name] = t
+	}
+	names := f.compoNames()
+	f.mutex.Unlock()
+
+	var errs LintErrors
+
+	for _, name := range names {
+		c := reflect.New(types[name]).Interface().(Compo)
+		errs = append(errs, lintCompo(c, types, true)...)
+	}
+
+	if len(errs) != 0 {
+		return errs
+	}
 
+	return nil
+}
+
+// CompoNames returns the sorted names of the registered components.
+func (f *Factory) CompoNames() []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.compoNames()
+}
+
+func (f *Factory) compoNames() []string {
+	names := make([]string, 0, len(f.types))
+	for name := range f.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 // IsCompoRegistered reports whether the named component is registered.
@@ -71,3 +71,11 @@ func TestFactory(t *testing.T) {
 		})
 	}
 }
+
+func TestFactoryCompoNames(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&tests.Foo{})
+	f.RegisterCompo(&tests.Bar{})
+
+	assert.Equal(t, []string{"tests.bar", "tests.foo"}, f.CompoNames())
+}
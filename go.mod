@@ -1,5 +1,7 @@
 module github.com/murlokswarm/app
 
+go 1.14
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/disintegration/imaging v1.5.0
@@ -34,6 +34,7 @@ const (
 	DescriptionMeta     MetaName = "description"
 	GeneratorMeta       MetaName = "generator"
 	KeywordsMeta        MetaName = "keywords"
+	ThemeColorMeta      MetaName = "theme-color"
 	ViewportMeta        MetaName = "viewport"
 )
 
@@ -0,0 +1,467 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LocaleChanged is the name of the event dispatched when the locale is
+// changed with SetLocale, or when a catalog used by the current locale is
+// loaded while the app is running. Its argument is the current locale.
+// Mounted components are rendered again when it occurs.
+const LocaleChanged = "app.localeChanged"
+
+const defaultLocale = "en"
+
+// LoadCatalog loads the message catalog of the given locale from json data.
+//
+// Catalogs map message keys to translations. Messages that depend on a
+// quantity are objects keyed by plural category (zero, one, two, few, many
+// and other):
+//
+//	{
+//	    "hello": "Bonjour %s",
+//	    "items": {
+//	        "one": "%d élément",
+//	        "other": "%d éléments"
+//	    }
+//	}
+//
+// Catalogs located in the resources/locales directory and named after their
+// locale, like fr.json or pt-BR.json, are loaded automatically.
+// Loading a catalog for an already loaded locale merges its messages.
+func LoadCatalog(locale string, data []byte) error {
+	if err := i18n.LoadCatalog(locale, data); err != nil {
+		return err
+	}
+
+	if driver != nil && i18n.IsUsed(normalizeLocale(locale)) {
+		events.Dispatch(LocaleChanged, i18n.Locale())
+	}
+	return nil
+}
+
+// SetLocale sets the locale used to translate messages and to format numbers
+// and dates. Locales are BCP 47 language tags like en, fr or pt-BR.
+// It dispatches the LocaleChanged event so that the mounted components are
+// rendered again.
+func SetLocale(locale string) {
+	locale = normalizeLocale(locale)
+	if !i18n.SetLocale(locale) {
+		return
+	}
+
+	if driver != nil {
+		events.Dispatch(LocaleChanged, locale)
+	}
+}
+
+// Locale returns the current locale.
+// It defaults to the one defined in the LANG environment variable, or en when
+// it is not set.
+func Locale() string {
+	return i18n.Locale()
+}
+
+// T returns the translation of the message with the given key in the current
+// locale. The translation is formatted with the given arguments according to
+// a format specifier.
+// It falls back to the base language, then to en, and returns the key,
+// unformatted, when no translation is found.
+func T(key string, a ...interface{}) string {
+	msg, ok := i18n.Message(key, "")
+	if !ok {
+		return key
+	}
+	return format(msg, a...)
+}
+
+// Plural returns the translation of the message with the given key that
+// matches the quantity n in the current locale. The translation is formatted
+// with the given arguments according to a format specifier:
+//
+//	app.Plural("items", len(items), len(items))
+//
+// The other plural form is used when the one of n is not translated. The key
+// is returned, unformatted, when no translation is found.
+func Plural(key string, n int, a ...interface{}) string {
+	msg, ok := i18n.Message(key, pluralCategory(i18n.Locale(), n))
+	if !ok {
+		return key
+	}
+	return format(msg, a...)
+}
+
+// Dir returns the text direction of the current locale: rtl for right to
+// left languages or ltr otherwise.
+func Dir() string {
+	switch language(i18n.Locale()) {
+	case "ar", "fa", "he", "ps", "ur", "yi":
+		return "rtl"
+	default:
+		return "ltr"
+	}
+}
+
+// FormatNumber formats the given number with the decimal and group
+// separators of the current locale.
+// decimals is the number of digits after the decimal separator.
+func FormatNumber(n float64, decimals int) string {
+	sep := numberSeparators(i18n.Locale())
+
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	s := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	integer := s
+	fraction := ""
+
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer = s[:i]
+		fraction = s[i+1:]
+	}
+
+	var b strings.Builder
+	if n < 0 && strings.Trim(s, "0.") != "" {
+		b.WriteByte('-')
+	}
+
+	for i, r := range integer {
+		if i != 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(sep.group)
+		}
+		b.WriteRune(r)
+	}
+
+	if len(fraction) != 0 {
+		b.WriteString(sep.decimal)
+		b.WriteString(fraction)
+	}
+
+	return b.String()
+}
+
+// FormatDate formats the date of the given time with the short date layout
+// of the current locale.
+func FormatDate(t time.Time) string {
+	locale := i18n.Locale()
+
+	if layout, ok := dateLayouts[locale]; ok {
+		return t.Format(layout)
+	}
+
+	if layout, ok := dateLayouts[language(locale)]; ok {
+		return t.Format(layout)
+	}
+
+	return t.Format(dateLayouts[defaultLocale])
+}
+
+func format(msg string, a ...interface{}) string {
+	if len(a) == 0 {
+		return msg
+	}
+
+	return fmt.Sprintf(msg, a...)
+}
+
+type message struct {
+	text   string
+	plural map[string]string
+}
+
+func (m *message) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &m.text); err == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, &m.plural)
+}
+
+func (m message) String(category string) string {
+	if len(category) == 0 || m.plural == nil {
+		if m.plural != nil {
+			return m.plural["other"]
+		}
+		return m.text
+	}
+
+	if s, ok := m.plural[category]; ok {
+		return s
+	}
+
+	if s, ok := m.plural["other"]; ok {
+		return s
+	}
+
+	return m.text
+}
+
+type catalog map[string]message
+
+func newI18nRegistry() *i18nRegistry {
+	return &i18nRegistry{
+		locale:   normalizeLocale(os.Getenv("LANG")),
+		catalogs: make(map[string]catalog),
+	}
+}
+
+type i18nRegistry struct {
+	mutex    sync.RWMutex
+	once     sync.Once
+	locale   string
+	catalogs map[string]catalog
+}
+
+func (r *i18nRegistry) LoadCatalog(locale string, data []byte) error {
+	var c catalog
+	if err := json.Unmarshal(data, &c); err != nil {
+		return errors.Wrapf(err, "decoding %s catalog failed", locale)
+	}
+
+	locale = normalizeLocale(locale)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dst, ok := r.catalogs[locale]
+	if !ok {
+		r.catalogs[locale] = c
+		return nil
+	}
+
+	for k, m := range c {
+		dst[k] = m
+	}
+
+	return nil
+}
+
+// loadResources loads the catalogs located in the resources directory.
+// It is done once, on the first translation that occurs while the app is
+// running.
+func (r *i18nRegistry) loadResources() {
+	if driver == nil {
+		return
+	}
+
+	r.once.Do(func() {
+		dir := Resources("locales")
+
+		filenames, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+		for _, filename := range filenames {
+			data, err := ioutil.ReadFile(filename)
+			if err != nil {
+				Logf("loading catalog failed: %s", err)
+				continue
+			}
+
+			locale := strings.TrimSuffix(filepath.Base(filename), ".json")
+			if err = r.LoadCatalog(locale, data); err != nil {
+				Logf("loading catalog failed: %s", err)
+			}
+		}
+	})
+}
+
+func (r *i18nRegistry) SetLocale(locale string) (changed bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	changed = r.locale != locale
+	r.locale = locale
+	return changed
+}
+
+func (r *i18nRegistry) Locale() string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.locale
+}
+
+// IsUsed reports whether the catalog of the given locale is used to translate
+// messages in the current locale.
+func (r *i18nRegistry) IsUsed(locale string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return locale == r.locale || locale == language(r.locale) || locale == defaultLocale
+}
+
+func (r *i18nRegistry) Message(key, category string) (msg string, ok bool) {
+	r.loadResources()
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, locale := range []string{r.locale, language(r.locale), defaultLocale} {
+		if m, ok := r.catalogs[locale][key]; ok {
+			return m.String(category), true
+		}
+	}
+
+	return "", false
+}
+
+// normalizeLocale converts locales like fr_FR.UTF-8 to BCP 47 language tags
+// like fr-FR.
+func normalizeLocale(locale string) string {
+	if i := strings.IndexAny(locale, ".@"); i >= 0 {
+		locale = locale[:i]
+	}
+
+	locale = strings.Replace(locale, "_", "-", -1)
+
+	if len(locale) == 0 || locale == "C" || locale == "POSIX" {
+		return defaultLocale
+	}
+
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+
+	for i := 1; i < len(parts); i++ {
+		if len(parts[i]) == 2 {
+			parts[i] = strings.ToUpper(parts[i])
+		}
+	}
+
+	return strings.Join(parts, "-")
+}
+
+func language(locale string) string {
+	if i := strings.IndexByte(locale, '-'); i >= 0 {
+		return locale[:i]
+	}
+
+	return locale
+}
+
+// pluralCategory returns the plural category of n in the given locale.
+// It implements a simplified version of the CLDR plural rules for integers.
+func pluralCategory(locale string, n int) string {
+	if n < 0 {
+		n = -n
+	}
+
+	mod10 := n % 10
+	mod100 := n % 100
+
+	switch language(locale) {
+	case "ja", "ko", "zh", "th", "vi", "id", "ms", "tr":
+		return "other"
+
+	case "fr", "hy", "kab":
+		if n == 0 || n == 1 {
+			return "one"
+		}
+		return "other"
+
+	case "ru", "uk", "be", "hr", "sr", "bs":
+		switch {
+		case mod10 == 1 && mod100 != 11:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+
+	case "pl":
+		switch {
+		case n == 1:
+			return "one"
+		case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+			return "few"
+		default:
+			return "many"
+		}
+
+	case "cs", "sk":
+		switch {
+		case n == 1:
+			return "one"
+		case n >= 2 && n <= 4:
+			return "few"
+		default:
+			return "other"
+		}
+
+	case "ar":
+		switch {
+		case n == 0:
+			return "zero"
+		case n == 1:
+			return "one"
+		case n == 2:
+			return "two"
+		case mod100 >= 3 && mod100 <= 10:
+			return "few"
+		case mod100 >= 11:
+			return "many"
+		default:
+			return "other"
+		}
+
+	default:
+		if n == 1 {
+			return "one"
+		}
+		return "other"
+	}
+}
+
+type separators struct {
+	decimal string
+	group   string
+}
+
+func numberSeparators(locale string) separators {
+	switch language(locale) {
+	case "de", "es", "it", "nl", "pt", "id", "tr", "da", "el", "ro", "hr", "sr":
+		return separators{decimal: ",", group: "."}
+
+	case "fr", "ru", "uk", "pl", "cs", "sk", "sv", "fi", "nb", "no", "hu", "bg":
+		return separators{decimal: ",", group: "\u00a0"}
+
+	default:
+		return separators{decimal: ".", group: ","}
+	}
+}
+
+var dateLayouts = map[string]string{
+	"en":    "01/02/2006",
+	"en-AU": "02/01/2006",
+	"en-GB": "02/01/2006",
+	"en-IE": "02/01/2006",
+	"en-IN": "02/01/2006",
+	"en-NZ": "02/01/2006",
+	"ar":    "02/01/2006",
+	"de":    "02.01.2006",
+	"es":    "02/01/2006",
+	"fi":    "2.1.2006",
+	"fr":    "02/01/2006",
+	"fr-CA": "2006-01-02",
+	"he":    "02.01.2006",
+	"it":    "02/01/2006",
+	"ja":    "2006/01/02",
+	"ko":    "2006. 01. 02.",
+	"nl":    "02-01-2006",
+	"pl":    "02.01.2006",
+	"pt":    "02/01/2006",
+	"ru":    "02.01.2006",
+	"sv":    "2006-01-02",
+	"uk":    "02.01.2006",
+	"zh":    "2006/01/02",
+}
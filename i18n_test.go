@@ -0,0 +1,208 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocale(t *testing.T) {
+	tests := []struct {
+		locale   string
+		expected string
+	}{
+		{locale: "", expected: "en"},
+		{locale: "C", expected: "en"},
+		{locale: "fr", expected: "fr"},
+		{locale: "fr_FR.UTF-8", expected: "fr-FR"},
+		{locale: "pt-br", expected: "pt-BR"},
+		{locale: "zh-Hant-TW", expected: "zh-Hant-TW"},
+		{locale: "de_DE@euro", expected: "de-DE"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			assert.Equal(t, test.expected, normalizeLocale(test.locale))
+		})
+	}
+}
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		locale   string
+		n        int
+		expected string
+	}{
+		{locale: "en", n: 0, expected: "other"},
+		{locale: "en", n: 1, expected: "one"},
+		{locale: "en-GB", n: 2, expected: "other"},
+		{locale: "fr", n: 0, expected: "one"},
+		{locale: "fr", n: 2, expected: "other"},
+		{locale: "ja", n: 1, expected: "other"},
+		{locale: "ru", n: 21, expected: "one"},
+		{locale: "ru", n: 11, expected: "many"},
+		{locale: "ru", n: 3, expected: "few"},
+		{locale: "ru", n: 13, expected: "many"},
+		{locale: "pl", n: 1, expected: "one"},
+		{locale: "pl", n: 22, expected: "few"},
+		{locale: "pl", n: 21, expected: "many"},
+		{locale: "cs", n: 4, expected: "few"},
+		{locale: "cs", n: 5, expected: "other"},
+		{locale: "ar", n: 0, expected: "zero"},
+		{locale: "ar", n: 2, expected: "two"},
+		{locale: "ar", n: 105, expected: "few"},
+		{locale: "ar", n: 11, expected: "many"},
+		{locale: "ar", n: 100, expected: "other"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			assert.Equal(t, test.expected, pluralCategory(test.locale, test.n))
+		})
+	}
+}
+
+func TestTranslations(t *testing.T) {
+	defer i18n.SetLocale(i18n.Locale())
+
+	err := LoadCatalog("en", []byte(`{
+		"hello": "Hello %s",
+		"bye": "Bye",
+		"items": {"one": "%d item", "other": "%d items"}
+	}`))
+	require.NoError(t, err)
+
+	err = LoadCatalog("fr", []byte(`{
+		"hello": "Bonjour %s",
+		"items": {"one": "%d élément", "other": "%d éléments"}
+	}`))
+	require.NoError(t, err)
+
+	err = LoadCatalog("fr_CA", []byte(`{"hello": "Allo %s"}`))
+	require.NoError(t, err)
+
+	err = LoadCatalog("de", []byte(`{"hello": 42}`))
+	assert.Error(t, err)
+
+	tests := []struct {
+		scenario string
+		locale   string
+		text     func() string
+		expected string
+	}{
+		{
+			scenario: "translation",
+			locale:   "fr",
+			text:     func() string { return T("hello", "Max") },
+			expected: "Bonjour Max",
+		},
+		{
+			scenario: "regional translation",
+			locale:   "fr-CA",
+			text:     func() string { return T("hello", "Max") },
+			expected: "Allo Max",
+		},
+		{
+			scenario: "base language fallback",
+			locale:   "fr-CA",
+			text:     func() string { return Plural("items", 0, 0) },
+			expected: "0 élément",
+		},
+		{
+			scenario: "default locale fallback",
+			locale:   "fr",
+			text:     func() string { return T("bye") },
+			expected: "Bye",
+		},
+		{
+			scenario: "key fallback",
+			locale:   "fr",
+			text:     func() string { return T("unknown") },
+			expected: "unknown",
+		},
+		{
+			scenario: "key fallback with arguments",
+			locale:   "fr",
+			text:     func() string { return T("greeting %s", "Bob") },
+			expected: "greeting %s",
+		},
+		{
+			scenario: "plural key fallback",
+			locale:   "fr",
+			text:     func() string { return Plural("unknown items", 2, 2) },
+			expected: "unknown items",
+		},
+		{
+			scenario: "plural",
+			locale:   "en",
+			text:     func() string { return Plural("items", 3, 3) },
+			expected: "3 items",
+		},
+		{
+			scenario: "plural other fallback",
+			locale:   "ar",
+			text:     func() string { return Plural("items", 2, 2) },
+			expected: "2 items",
+		},
+		{
+			scenario: "plural without quantity",
+			locale:   "en",
+			text:     func() string { return T("items", 2) },
+			expected: "2 items",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			SetLocale(test.locale)
+			assert.Equal(t, test.expected, test.text())
+		})
+	}
+}
+
+func TestI18nRegistryIsUsed(t *testing.T) {
+	r := newI18nRegistry()
+	r.SetLocale("fr-CA")
+
+	assert.True(t, r.IsUsed("fr-CA"))
+	assert.True(t, r.IsUsed("fr"))
+	assert.True(t, r.IsUsed("en"))
+	assert.False(t, r.IsUsed("de"))
+}
+
+func TestLocaleFormats(t *testing.T) {
+	defer i18n.SetLocale(i18n.Locale())
+
+	date := time.Date(1986, 2, 14, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		locale string
+		number string
+		date   string
+		dir    string
+	}{
+		{locale: "en", number: "-1,234,567.89", date: "02/14/1986", dir: "ltr"},
+		{locale: "en-GB", number: "-1,234,567.89", date: "14/02/1986", dir: "ltr"},
+		{locale: "fr-FR", number: "-1\u00a0234\u00a0567,89", date: "14/02/1986", dir: "ltr"},
+		{locale: "de", number: "-1.234.567,89", date: "14.02.1986", dir: "ltr"},
+		{locale: "ar", number: "-1,234,567.89", date: "14/02/1986", dir: "rtl"},
+		{locale: "he-IL", number: "-1,234,567.89", date: "14.02.1986", dir: "rtl"},
+		{locale: "xx", number: "-1,234,567.89", date: "02/14/1986", dir: "ltr"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.locale, func(t *testing.T) {
+			SetLocale(test.locale)
+			assert.Equal(t, test.number, FormatNumber(-1234567.891, 2))
+			assert.Equal(t, test.date, FormatDate(date))
+			assert.Equal(t, test.dir, Dir())
+		})
+	}
+
+	SetLocale("en")
+	assert.Equal(t, "0", FormatNumber(-0.1, 0))
+	assert.Equal(t, "999", FormatNumber(999, -1))
+	assert.Equal(t, "1,000", FormatNumber(1000, 0))
+}
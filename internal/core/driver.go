@@ -3,12 +3,15 @@ package core
 import (
 	"os"
 	"path/filepath"
+	"sync"
 
 	"github.com/murlokswarm/app"
 )
 
 // Driver is a base struct to embed in app.Driver implementations.
 type Driver struct {
+	storeOnce sync.Once
+	store     app.Store
 }
 
 // Run satisfies the app.Driver interface.
@@ -36,6 +39,14 @@ func (d *Driver) Storage(p ...string) string {
 	return s
 }
 
+// LocalStore satisfies the app.Driver interface.
+func (d *Driver) LocalStore() app.Store {
+	d.storeOnce.Do(func() {
+		d.store = NewFileStore(d.Storage("store"))
+	})
+	return d.store
+}
+
 // Render satisfies the app.Driver interface.
 func (d *Driver) Render(c app.Compo) {
 }
@@ -16,6 +16,8 @@ func TestDriver(t *testing.T) {
 	assert.NotEmpty(t, d.AppName())
 	assert.Equal(t, "resources", d.Resources())
 	assert.Equal(t, "storage", d.Storage())
+	assert.NotNil(t, d.LocalStore())
+	assert.True(t, d.LocalStore() == d.LocalStore())
 	d.Render(nil)
 	assert.Error(t, d.ElemByCompo(nil).Err())
 
@@ -19,6 +19,11 @@ func (m *Menu) Load(url string, v ...interface{}) {
 	m.SetErr(app.ErrNotSupported)
 }
 
+// Mount satisfies the app.Menu interface.
+func (m *Menu) Mount(c app.Compo) {
+	m.SetErr(app.ErrNotSupported)
+}
+
 // Compo satisfies the app.Menu interface.
 func (m *Menu) Compo() app.Compo {
 	return nil
@@ -34,6 +39,11 @@ func (m *Menu) Render(c app.Compo) {
 	m.SetErr(app.ErrNotSupported)
 }
 
+// CallNode satisfies the app.Menu interface.
+func (m *Menu) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	return app.ErrNotSupported
+}
+
 // Type satisfies the app.Menu interface.
 func (m *Menu) Type() string {
 	return "menu"
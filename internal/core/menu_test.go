@@ -58,11 +58,17 @@ func testMenu(t *testing.T, m app.Menu) {
 	m.Load("")
 	assert.Error(t, m.Err())
 
+	m.Mount(nil)
+	assert.Error(t, m.Err())
+
 	assert.Nil(t, m.Compo())
 	assert.False(t, m.Contains(nil))
 
 	m.Render(nil)
 	assert.Error(t, m.Err())
 
+	err := m.CallNode("", "focus", nil)
+	assert.Equal(t, app.ErrNotSupported, err)
+
 	assert.NotEmpty(t, m.Type())
 }
@@ -26,6 +26,11 @@ func (p *Page) Load(url string, v ...interface{}) {
 	p.SetErr(app.ErrNotSupported)
 }
 
+// Mount satisfies the app.Page interface.
+func (p *Page) Mount(c app.Compo) {
+	p.SetErr(app.ErrNotSupported)
+}
+
 // Compo satisfies the app.Page interface.
 func (p *Page) Compo() app.Compo {
 	return nil
@@ -41,6 +46,11 @@ func (p *Page) Render(c app.Compo) {
 	p.SetErr(app.ErrNotSupported)
 }
 
+// CallNode satisfies the app.Page interface.
+func (p *Page) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	return app.ErrNotSupported
+}
+
 // Reload satisfies the app.Page interface.
 func (p *Page) Reload() {
 	p.SetErr(app.ErrNotSupported)
@@ -66,6 +76,11 @@ func (p *Page) Next() {
 	p.SetErr(app.ErrNotSupported)
 }
 
+// EvalJS satisfies the app.JSEvaluator interface.
+func (p *Page) EvalJS(script string, out interface{}) error {
+	return app.ErrNotSupported
+}
+
 // URL satisfies the app.Page interface.
 func (p *Page) URL() *url.URL {
 	return nil
@@ -25,12 +25,21 @@ func TestPage(t *testing.T) {
 	p.Load("")
 	assert.Error(t, p.Err())
 
+	p.Mount(nil)
+	assert.Error(t, p.Err())
+
 	assert.Nil(t, p.Compo())
 	assert.False(t, p.Contains(nil))
 
 	p.Render(nil)
 	assert.Error(t, p.Err())
 
+	err := p.CallNode("", "focus", nil)
+	assert.Equal(t, app.ErrNotSupported, err)
+
+	err = p.EvalJS("", nil)
+	assert.Equal(t, app.ErrNotSupported, err)
+
 	p.Reload()
 	assert.Error(t, p.Err())
 
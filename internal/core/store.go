@@ -0,0 +1,135 @@
+package core
+
+import (
+	"encoding/base32"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/murlokswarm/app"
+)
+
+// FileStore is an app.Store implementation that stores values and blobs in
+// files.
+type FileStore struct {
+	dir   string
+	mutex sync.RWMutex
+}
+
+// NewFileStore creates a store that writes its files in the given directory.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{
+		dir: dir,
+	}
+}
+
+// Get satisfies the app.Store interface.
+func (s *FileStore) Get(key string, v interface{}) error {
+	b, err := s.read(s.filename("values", key))
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(b, v)
+}
+
+// Set satisfies the app.Store interface.
+func (s *FileStore) Set(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.write(s.filename("values", key), b)
+}
+
+// Del satisfies the app.Store interface.
+func (s *FileStore) Del(key string) error {
+	return s.remove(s.filename("values", key))
+}
+
+// GetBlob satisfies the app.Store interface.
+func (s *FileStore) GetBlob(key string) ([]byte, error) {
+	return s.read(s.filename("blobs", key))
+}
+
+// SetBlob satisfies the app.Store interface.
+func (s *FileStore) SetBlob(key string, b []byte) error {
+	return s.write(s.filename("blobs", key), b)
+}
+
+// DelBlob satisfies the app.Store interface.
+func (s *FileStore) DelBlob(key string) error {
+	return s.remove(s.filename("blobs", key))
+}
+
+// filename returns the file where the given key is stored. Keys are encoded
+// in base32 in order to always be a distinct file within the store
+// directory, including on case-insensitive file systems. The prefix keeps the
+// empty key a file name.
+func (s *FileStore) filename(kind, key string) string {
+	name := "_" + keyEncoding.EncodeToString([]byte(key))
+	return filepath.Join(s.dir, kind, name)
+}
+
+var keyEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+func (s *FileStore) read(filename string) ([]byte, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	b, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, app.ErrNotFound
+	}
+
+	return b, err
+}
+
+// write writes the given data in a temporary file that replaces the given
+// file once written. It prevents partial writes to corrupt the stored data.
+// The temporary file is unique so concurrent writes from stores that share
+// the directory do not interfere.
+func (s *FileStore) write(filename string, b []byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	dir := filepath.Dir(filename)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	f, err := ioutil.TempFile(dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(b)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = os.Chmod(f.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filename)
+}
+
+func (s *FileStore) remove(filename string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	err := os.Remove(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var s app.Store = NewFileStore(dir)
+
+	type settings struct {
+		Theme string
+		Size  int
+	}
+
+	var v settings
+	assert.Equal(t, app.ErrNotFound, s.Get("settings", &v))
+
+	require.NoError(t, s.Set("settings", settings{Theme: "dark", Size: 42}))
+	require.NoError(t, s.Get("settings", &v))
+	assert.Equal(t, settings{Theme: "dark", Size: 42}, v)
+
+	require.NoError(t, s.Del("settings"))
+	assert.Equal(t, app.ErrNotFound, s.Get("settings", &v))
+	assert.NoError(t, s.Del("settings"))
+
+	_, err = s.GetBlob("image")
+	assert.Equal(t, app.ErrNotFound, err)
+
+	require.NoError(t, s.SetBlob("image", []byte{1, 2, 3}))
+	b, err := s.GetBlob("image")
+	require.NoError(t, err)
+	assert.Equal(t, []byte{1, 2, 3}, b)
+
+	require.NoError(t, s.DelBlob("image"))
+	_, err = s.GetBlob("image")
+	assert.Equal(t, app.ErrNotFound, err)
+}
+
+func TestFileStoreKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	s := NewFileStore(filepath.Join(dir, "store"))
+
+	keys := []string{
+		"../outside",
+		"/absolute",
+		"dir/key",
+		"..",
+		"hello world",
+		"a:b",
+		"Foo",
+		"",
+	}
+
+	for _, k := range keys {
+		t.Run(k, func(t *testing.T) {
+			require.NoError(t, s.Set(k, k))
+
+			var v string
+			require.NoError(t, s.Get(k, &v))
+			assert.Equal(t, k, v)
+
+			filename, err := filepath.Rel(filepath.Join(dir, "store", "values"), s.filename("values", k))
+			require.NoError(t, err)
+			assert.Equal(t, filepath.Base(filename), filename)
+			assert.NotContains(t, filename, ":")
+		})
+	}
+
+	// Keys that only differ by case must not share a file on case-insensitive
+	// file systems.
+	assert.False(t, strings.EqualFold(s.filename("values", "Foo"), s.filename("values", "foo")))
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1)
+}
+
+func TestFileStoreConcurrentWrites(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goapp-store")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	stores := []*FileStore{NewFileStore(dir), NewFileStore(dir)}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			assert.NoError(t, stores[i%2].SetBlob("blob", []byte{byte(i)}))
+		}(i)
+	}
+	wg.Wait()
+
+	b, err := stores[0].GetBlob("blob")
+	require.NoError(t, err)
+	assert.Len(t, b, 1)
+
+	files, err := ioutil.ReadDir(filepath.Join(dir, "blobs"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, filepath.Base(stores[0].filename("blobs", "blob")), files[0].Name())
+}
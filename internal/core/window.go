@@ -24,6 +24,11 @@ func (w *Window) Load(url string, v ...interface{}) {
 	w.SetErr(app.ErrNotSupported)
 }
 
+// Mount satisfies the app.Window interface.
+func (w *Window) Mount(c app.Compo) {
+	w.SetErr(app.ErrNotSupported)
+}
+
 // Compo satisfies the app.Window interface.
 func (w *Window) Compo() app.Compo {
 	return nil
@@ -39,6 +44,11 @@ func (w *Window) Render(c app.Compo) {
 	w.SetErr(app.ErrNotSupported)
 }
 
+// CallNode satisfies the app.Window interface.
+func (w *Window) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	return app.ErrNotSupported
+}
+
 // Reload satisfies the app.Window interface.
 func (w *Window) Reload() {
 	w.SetErr(app.ErrNotSupported)
@@ -64,6 +74,11 @@ func (w *Window) Next() {
 	w.SetErr(app.ErrNotSupported)
 }
 
+// EvalJS satisfies the app.JSEvaluator interface.
+func (w *Window) EvalJS(script string, out interface{}) error {
+	return app.ErrNotSupported
+}
+
 // Position satisfies the app.Window interface.
 func (w *Window) Position() (x, y float64) {
 	w.SetErr(app.ErrNotSupported)
@@ -26,12 +26,21 @@ func TestWindow(t *testing.T) {
 	w.Load("")
 	assert.Error(t, w.Err())
 
+	w.Mount(nil)
+	assert.Error(t, w.Err())
+
 	assert.Nil(t, w.Compo())
 	assert.False(t, w.Contains(nil))
 
 	w.Render(nil)
 	assert.Error(t, w.Err())
 
+	err := w.CallNode("", "focus", nil)
+	assert.Equal(t, app.ErrNotSupported, err)
+
+	err = w.EvalJS("", nil)
+	assert.Equal(t, app.ErrNotSupported, err)
+
 	w.Reload()
 	assert.Error(t, w.Err())
 
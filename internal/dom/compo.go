@@ -12,6 +12,8 @@ import (
 type compo struct {
 	ID       string
 	ParentID string
+	Scope    string
+	Load     *asyncLoad
 	Compo    app.Compo
 	Events   *app.EventSubscriber
 }
@@ -2,15 +2,41 @@ package dom
 
 import (
 	"encoding/json"
+	"fmt"
+	"html"
 	"html/template"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
 )
 
 var converters = map[string]interface{}{
-	"raw":   rawHTML,
-	"compo": compoHTMLTag,
-	"time":  timeFormat,
-	"json":  jsonFormat,
+	"raw":    rawHTML,
+	"compo":  compoHTMLTag,
+	"time":   timeFormat,
+	"json":   jsonFormat,
+	"t":      app.T,
+	"plural": app.Plural,
+	"number": numberFormat,
+	"date":   app.FormatDate,
+	"dir":    app.Dir,
+
+	"url":      urlFormat,
+	"classes":  classList,
+	"style":    styleList,
+	"bytes":    bytesFormat,
+	"duration": durationFormat,
+	"truncate": truncate,
+	"default":  defaultValue,
+	"attr":     conditionalAttr,
 }
 
 func rawHTML(s string) template.HTML {
@@ -29,3 +55,330 @@ func jsonFormat(v interface{}) (string, error) {
 	b, err := json.Marshal(v)
 	return string(b), err
 }
+
+func numberFormat(v interface{}, decimals ...int) (string, error) {
+	d := 0
+	if len(decimals) != 0 {
+		d = decimals[0]
+	}
+
+	n, err := toFloat(v)
+	if err != nil {
+		return "", errors.Wrap(err, "number")
+	}
+
+	return app.FormatNumber(n, d), nil
+}
+
+// urlFormat returns the given url with the given query parameters. Parameters
+// are key value pairs:
+//
+//	{{url "main.contact" "id" .ID "tab" "info"}}
+func urlFormat(rawurl string, params ...interface{}) (string, error) {
+	if len(params)%2 != 0 {
+		return "", errors.New("url parameters are not key value pairs")
+	}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+
+	if len(u.Scheme) != 0 && !isSafeScheme(u.Scheme) {
+		return "", errors.Errorf("url scheme %s is not allowed", u.Scheme)
+	}
+
+	q := u.Query()
+
+	for i := 0; i < len(params); i += 2 {
+		k, ok := params[i].(string)
+		if !ok {
+			return "", errors.Errorf("url parameter key is not a string: %T", params[i])
+		}
+
+		q.Add(k, fmt.Sprint(params[i+1]))
+	}
+
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+func isSafeScheme(scheme string) bool {
+	switch strings.ToLower(scheme) {
+	case "http", "https", "mailto", "tel":
+		return true
+	default:
+		return false
+	}
+}
+
+// classList returns a space separated list of css classes. Arguments are
+// class names or maps of class names that are included when their value is
+// true:
+//
+//	{{classes "button" .ButtonStates}}
+func classList(classes ...interface{}) (string, error) {
+	list := make([]string, 0, len(classes))
+
+	for _, c := range classes {
+		switch c := c.(type) {
+		case string:
+			if len(c) != 0 {
+				list = append(list, c)
+			}
+
+		case map[string]bool:
+			names := make([]string, 0, len(c))
+			for name, ok := range c {
+				if ok && len(name) != 0 {
+					names = append(names, name)
+				}
+			}
+
+			sort.Strings(names)
+			list = append(list, names...)
+
+		default:
+			return "", errors.Errorf("classes does not support %T", c)
+		}
+	}
+
+	return strings.Join(list, " "), nil
+}
+
+var cssPropertyRegexp = regexp.MustCompile(`^-?[a-zA-Z][a-zA-Z0-9-]*$`)
+
+// styleList returns inline css declarations. Arguments are property value
+// pairs or maps of properties. Declarations with an empty or unsafe value are
+// omitted:
+//
+//	<div style="{{style "width" .Width "color" .Color}}">
+func styleList(styles ...interface{}) (template.CSS, error) {
+	var props []string
+	values := make(map[string]string)
+
+	add := func(k string, v interface{}) error {
+		if !cssPropertyRegexp.MatchString(k) {
+			return errors.Errorf("%q is not a css property", k)
+		}
+
+		if _, ok := values[k]; !ok {
+			props = append(props, k)
+		}
+
+		values[k] = strings.TrimSpace(fmt.Sprint(v))
+		return nil
+	}
+
+	for i := 0; i < len(styles); i++ {
+		switch s := styles[i].(type) {
+		case string:
+			if i+1 == len(styles) {
+				return "", errors.Errorf("css property %s does not have a value", s)
+			}
+
+			if err := add(s, styles[i+1]); err != nil {
+				return "", err
+			}
+			i++
+
+		case map[string]string:
+			keys := make([]string, 0, len(s))
+			for k := range s {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				if err := add(k, s[k]); err != nil {
+					return "", err
+				}
+			}
+
+		default:
+			return "", errors.Errorf("style does not support %T", s)
+		}
+	}
+
+	decls := make([]string, 0, len(props))
+	for _, p := range props {
+		if v := values[p]; len(v) != 0 && isSafeCSSValue(v) {
+			decls = append(decls, p+": "+v)
+		}
+	}
+
+	return template.CSS(strings.Join(decls, "; ")), nil
+}
+
+func isSafeCSSValue(v string) bool {
+	if strings.ContainsAny(v, "\"'\\;{}<>`@") || strings.Contains(v, "/*") {
+		return false
+	}
+
+	v = strings.ToLower(v)
+	return !strings.Contains(v, "expression(") && !strings.Contains(v, "url(")
+}
+
+var byteUnits = []string{"B", "KB", "MB", "GB", "TB", "PB", "EB"}
+
+// bytesFormat returns a human readable representation of a number of bytes,
+// like 1.5 MB. Units are multiples of 1024.
+func bytesFormat(v interface{}) (string, error) {
+	n, err := toFloat(v)
+	if err != nil {
+		return "", errors.Wrap(err, "bytes")
+	}
+
+	i := 0
+	for ; math.Abs(n) >= 1024 && i < len(byteUnits)-1; i++ {
+		n /= 1024
+	}
+
+	if i == 0 {
+		return strconv.FormatFloat(n, 'f', 0, 64) + " " + byteUnits[i], nil
+	}
+
+	s := strconv.FormatFloat(n, 'f', 1, 64)
+	s = strings.TrimSuffix(s, ".0")
+	return s + " " + byteUnits[i], nil
+}
+
+// durationFormat returns a short human readable representation of the given
+// duration, like 1h 5m. It keeps the two most significant units.
+func durationFormat(d time.Duration) string {
+	if d < 0 {
+		return "-" + durationFormat(-d)
+	}
+
+	if d < time.Second {
+		return d.Round(time.Millisecond).String()
+	}
+
+	units := []struct {
+		size time.Duration
+		name string
+	}{
+		{size: 24 * time.Hour, name: "d"},
+		{size: time.Hour, name: "h"},
+		{size: time.Minute, name: "m"},
+		{size: time.Second, name: "s"},
+	}
+
+	var parts []string
+
+	for _, u := range units {
+		if len(parts) == 2 {
+			break
+		}
+
+		if n := d / u.size; n > 0 || len(parts) != 0 {
+			if n > 0 {
+				parts = append(parts, fmt.Sprintf("%d%s", n, u.name))
+			} else {
+				parts = append(parts, "")
+			}
+			d -= n * u.size
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(parts, " "))
+}
+
+// truncate shortens the given text to n characters, ellipsis included. It is
+// designed to be used in pipelines:
+//
+//	{{.Description | truncate 80}}
+func truncate(n int, s string) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+
+	if n <= 1 {
+		return string(r[:n])
+	}
+
+	return strings.TrimSpace(string(r[:n-1])) + "…"
+}
+
+// defaultValue returns v when it is not a zero value or def otherwise. It is
+// designed to be used in pipelines:
+//
+//	{{.Name | default "Anonymous"}}
+func defaultValue(def, v interface{}) interface{} {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || rv.IsZero() {
+		return def
+	}
+
+	return v
+}
+
+var attrNameRegexp = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.-]*$`)
+
+// conditionalAttr returns an html attribute. Boolean values set a valueless
+// attribute when they are true. Other values set the attribute when they are
+// not a zero value:
+//
+//	<button {{attr "disabled" .Busy}} {{attr "title" .Tooltip}}>
+//
+// Event handler attributes can't be set this way.
+func conditionalAttr(name string, v interface{}) (template.HTMLAttr, error) {
+	if !attrNameRegexp.MatchString(name) {
+		return "", errors.Errorf("%q is not an attribute name", name)
+	}
+
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "on") {
+		return "", errors.Errorf("event handler %s can't be set with attr", name)
+	}
+
+	if b, ok := v.(bool); ok {
+		if b {
+			return template.HTMLAttr(name), nil
+		}
+		return "", nil
+	}
+
+	if rv := reflect.ValueOf(v); !rv.IsValid() || rv.IsZero() {
+		return "", nil
+	}
+
+	value := fmt.Sprint(v)
+
+	if isURLAttr(name) {
+		u, err := url.Parse(value)
+		if err != nil || (len(u.Scheme) != 0 && !isSafeScheme(u.Scheme)) {
+			return "", nil
+		}
+	}
+
+	return template.HTMLAttr(name + `="` + html.EscapeString(value) + `"`), nil
+}
+
+func isURLAttr(name string) bool {
+	switch name {
+	case "href", "src", "action", "formaction", "poster", "cite", "data",
+		"background", "xlink:href":
+		return true
+	default:
+		return false
+	}
+}
+
+func toFloat(v interface{}) (float64, error) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+
+	default:
+		return 0, errors.Errorf("%T is not a number", v)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRawHTML(t *testing.T) {
@@ -27,3 +28,214 @@ func TestJSONFormat(t *testing.T) {
 	s, _ := jsonFormat(42)
 	assert.Equal(t, "42", s)
 }
+
+func TestNumberFormat(t *testing.T) {
+	s, err := numberFormat(uint8(42))
+	require.NoError(t, err)
+	assert.Equal(t, "42", s)
+
+	s, err = numberFormat(float32(0.5), 2)
+	require.NoError(t, err)
+	assert.Equal(t, "0.50", s)
+
+	_, err = numberFormat("42")
+	assert.Error(t, err)
+}
+
+func TestURLFormat(t *testing.T) {
+	tests := []struct {
+		scenario string
+		url      string
+		params   []interface{}
+		expected string
+		err      bool
+	}{
+		{scenario: "component", url: "main.hello", expected: "main.hello"},
+		{
+			scenario: "query params",
+			url:      "main.hello",
+			params:   []interface{}{"name", "Max & co", "id", 42},
+			expected: "main.hello?id=42&name=Max+%26+co",
+		},
+		{
+			scenario: "existing query params",
+			url:      "https://murlok.io/search?q=go",
+			params:   []interface{}{"page", 2},
+			expected: "https://murlok.io/search?page=2&q=go",
+		},
+		{scenario: "unsafe scheme", url: "javascript:alert(1)", err: true},
+		{scenario: "odd params", url: "main.hello", params: []interface{}{"id"}, err: true},
+		{scenario: "non string key", url: "main.hello", params: []interface{}{1, 2}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			s, err := urlFormat(test.url, test.params...)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, s)
+		})
+	}
+}
+
+func TestClassList(t *testing.T) {
+	s, err := classList("button", "", map[string]bool{
+		"disabled": false,
+		"primary":  true,
+		"active":   true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "button active primary", s)
+
+	_, err = classList(42)
+	assert.Error(t, err)
+}
+
+func TestStyleList(t *testing.T) {
+	tests := []struct {
+		scenario string
+		styles   []interface{}
+		expected template.CSS
+		err      bool
+	}{
+		{
+			scenario: "pairs",
+			styles:   []interface{}{"width", "42px", "color", "red"},
+			expected: "width: 42px; color: red",
+		},
+		{
+			scenario: "map",
+			styles:   []interface{}{map[string]string{"top": "0", "left": "1px"}},
+			expected: "left: 1px; top: 0",
+		},
+		{
+			scenario: "override and empty values",
+			styles:   []interface{}{"color", "red", "width", "", "color", "blue"},
+			expected: "color: blue",
+		},
+		{
+			scenario: "unsafe values",
+			styles: []interface{}{
+				"background", "url(javascript:alert(1))",
+				"width", "expression(alert(1))",
+				"color", "red; position: fixed",
+			},
+			expected: "",
+		},
+		{scenario: "bad property", styles: []interface{}{"col:or", "red"}, err: true},
+		{scenario: "missing value", styles: []interface{}{"color"}, err: true},
+		{scenario: "unsupported", styles: []interface{}{42}, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			s, err := styleList(test.styles...)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, s)
+		})
+	}
+}
+
+func TestBytesFormat(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected string
+	}{
+		{value: 0, expected: "0 B"},
+		{value: 1023, expected: "1023 B"},
+		{value: uint64(1024), expected: "1 KB"},
+		{value: 1536, expected: "1.5 KB"},
+		{value: 5.5 * 1024 * 1024 * 1024, expected: "5.5 GB"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			s, err := bytesFormat(test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, s)
+		})
+	}
+
+	_, err := bytesFormat("42")
+	assert.Error(t, err)
+}
+
+func TestDurationFormat(t *testing.T) {
+	tests := []struct {
+		duration time.Duration
+		expected string
+	}{
+		{duration: 0, expected: "0s"},
+		{duration: 250 * time.Millisecond, expected: "250ms"},
+		{duration: 42 * time.Second, expected: "42s"},
+		{duration: time.Hour + 5*time.Minute + 3*time.Second, expected: "1h 5m"},
+		{duration: time.Hour + 3*time.Second, expected: "1h"},
+		{duration: 50 * time.Hour, expected: "2d 2h"},
+		{duration: -90 * time.Second, expected: "-1m 30s"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			assert.Equal(t, test.expected, durationFormat(test.duration))
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "hello", truncate(5, "hello"))
+	assert.Equal(t, "héll…", truncate(5, "héllo world"))
+	assert.Equal(t, "hello…", truncate(7, "hello world"))
+	assert.Equal(t, "h", truncate(1, "hello"))
+}
+
+func TestDefaultValue(t *testing.T) {
+	assert.Equal(t, "Anonymous", defaultValue("Anonymous", ""))
+	assert.Equal(t, "Anonymous", defaultValue("Anonymous", nil))
+	assert.Equal(t, 42, defaultValue(0, 42))
+}
+
+func TestConditionalAttr(t *testing.T) {
+	tests := []struct {
+		scenario string
+		name     string
+		value    interface{}
+		expected template.HTMLAttr
+		err      bool
+	}{
+		{scenario: "true", name: "disabled", value: true, expected: "disabled"},
+		{scenario: "false", name: "disabled", value: false},
+		{scenario: "zero value", name: "title", value: ""},
+		{
+			scenario: "value",
+			name:     "title",
+			value:    `"hello" <world>`,
+			expected: `title="&#34;hello&#34; &lt;world&gt;"`,
+		},
+		{scenario: "url", name: "href", value: "main.hello", expected: `href="main.hello"`},
+		{scenario: "unsafe url", name: "href", value: "javascript:alert(1)"},
+		{scenario: "event handler", name: "onclick", value: "Click", err: true},
+		{scenario: "bad name", name: `x="y"`, value: true, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			attr, err := conditionalAttr(test.name, test.value)
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, attr)
+		})
+	}
+}
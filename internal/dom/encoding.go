@@ -0,0 +1,288 @@
+package dom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"strconv"
+)
+
+// Encoding describes how node changes are encoded when they are synchronized
+// with a remote dom.
+type Encoding int
+
+const (
+	// JSONEncoding passes the changes to the Sync function as they are. They
+	// are usually encoded in JSON by drivers.
+	JSONEncoding Encoding = iota
+
+	// CompactEncoding encodes the changes in a compact JSON array. Node
+	// identifiers, types and attribute keys are interned and actions are
+	// numeric codes. The Sync function is called with a []byte.
+	CompactEncoding
+
+	// BinaryEncoding encodes the changes like CompactEncoding but in a binary
+	// frame. The Sync function is called with a []byte.
+	BinaryEncoding
+)
+
+// maxInterned is the number of interned strings above which the interning
+// table is reset.
+const maxInterned = 1 << 16
+
+// Change fields that are encoded when they are set. The field order is the
+// order in which they are encoded.
+const (
+	compoIDField = 1 << iota
+	typeField
+	namespaceField
+	keyField
+	valueField
+	childIDField
+	newChildIDField
+	isCompoField
+	enterField
+	leaveField
+)
+
+// changeEncoder encodes changes for the compact and binary encodings.
+//
+// Encoded changes start with a reset flag and the strings to intern. Each
+// change is then encoded as its action code, its interned node id, a mask of
+// the set fields and the set fields. Strings are referred by their index in
+// the interning table, except attribute values and texts that are rarely
+// repeated. The interning table is kept between encodings and is reset when
+// the reset flag is 1.
+type changeEncoder struct {
+	strings map[string]int
+	added   []string
+	reset   bool
+}
+
+// Reset clears the interning table. The next encoded changes ask the remote
+// dom to clear its table too.
+func (e *changeEncoder) Reset() {
+	e.strings = nil
+	e.reset = true
+}
+
+// intern returns the index of the given string in the interning table. The
+// string is added to the table when it is not already there.
+func (e *changeEncoder) intern(s string) int {
+	i, ok := e.strings[s]
+	if !ok {
+		i = len(e.strings)
+		e.strings[s] = i
+		e.added = append(e.added, s)
+	}
+
+	return i
+}
+
+// fields returns the mask of the set fields of the given change.
+func fields(c change) int {
+	mask := 0
+
+	set := func(field int, ok bool) {
+		if ok {
+			mask |= field
+		}
+	}
+
+	set(compoIDField, len(c.CompoID) != 0)
+	set(typeField, len(c.Type) != 0)
+	set(namespaceField, len(c.Namespace) != 0)
+	set(keyField, len(c.Key) != 0)
+	set(valueField, len(c.Value) != 0)
+	set(childIDField, len(c.ChildID) != 0)
+	set(newChildIDField, len(c.NewChildID) != 0)
+	set(isCompoField, c.IsCompo)
+	set(enterField, len(c.Enter) != 0)
+	set(leaveField, len(c.Leave) != 0)
+	return mask
+}
+
+// encodedChange is a change where strings are replaced by their index in the
+// interning table.
+type encodedChange struct {
+	action int
+	nodeID int
+	mask   int
+	ids    []int
+	value  string
+}
+
+func (e *changeEncoder) encodeChange(c change, ids []int) encodedChange {
+	ec := encodedChange{
+		action: int(c.Action),
+		nodeID: e.intern(c.NodeID),
+		mask:   fields(c),
+		ids:    ids[:0],
+		value:  c.Value,
+	}
+
+	for _, f := range []struct {
+		field int
+		value string
+	}{
+		{field: compoIDField, value: c.CompoID},
+		{field: typeField, value: c.Type},
+		{field: namespaceField, value: c.Namespace},
+		{field: keyField, value: c.Key},
+		{field: childIDField, value: c.ChildID},
+		{field: newChildIDField, value: c.NewChildID},
+		{field: enterField, value: c.Enter},
+		{field: leaveField, value: c.Leave},
+	} {
+		if ec.mask&f.field != 0 {
+			ec.ids = append(ec.ids, e.intern(f.value))
+		}
+	}
+
+	return ec
+}
+
+// encodeChanges encodes the given changes and returns the reset flag and the
+// strings added to the interning table.
+func (e *changeEncoder) encodeChanges(changes []change, each func(encodedChange)) (reset bool, added []string) {
+	if e.strings == nil || len(e.strings) >= maxInterned {
+		e.strings = make(map[string]int, 256)
+		e.reset = true
+	}
+
+	e.added = e.added[:0]
+	ids := make([]int, 0, 8)
+
+	for _, c := range changes {
+		each(e.encodeChange(c, ids))
+	}
+
+	reset = e.reset
+	e.reset = false
+	return reset, e.added
+}
+
+// Compact encodes the given changes in a JSON array:
+//
+//	[reset, [strings...], [action, nodeID, mask, fields...], ...]
+func (e *changeEncoder) Compact(changes []change) []byte {
+	var ops bytes.Buffer
+
+	reset, added := e.encodeChanges(changes, func(c encodedChange) {
+		ops.WriteString(",[")
+		ops.WriteString(strconv.Itoa(c.action))
+		ops.WriteByte(',')
+		ops.WriteString(strconv.Itoa(c.nodeID))
+		ops.WriteByte(',')
+		ops.WriteString(strconv.Itoa(c.mask))
+
+		ids := c.ids
+		for _, field := range encodedFields {
+			if c.mask&field == 0 || field == isCompoField {
+				continue
+			}
+
+			ops.WriteByte(',')
+
+			if field == valueField {
+				v, _ := json.Marshal(c.value)
+				ops.Write(v)
+				continue
+			}
+
+			ops.WriteString(strconv.Itoa(ids[0]))
+			ids = ids[1:]
+		}
+
+		ops.WriteByte(']')
+	})
+
+	var b bytes.Buffer
+	b.WriteByte('[')
+
+	if reset {
+		b.WriteByte('1')
+	} else {
+		b.WriteByte('0')
+	}
+
+	strs, _ := json.Marshal(added)
+	if len(added) == 0 {
+		strs = []byte("[]")
+	}
+
+	b.WriteByte(',')
+	b.Write(strs)
+	b.Write(ops.Bytes())
+	b.WriteByte(']')
+	return b.Bytes()
+}
+
+// Binary encodes the given changes in a binary frame. Numbers are unsigned
+// varints and strings are prefixed by their length in bytes:
+//
+//	reset strings-count strings... changes-count changes...
+func (e *changeEncoder) Binary(changes []change) []byte {
+	var ops bytes.Buffer
+	buf := make([]byte, binary.MaxVarintLen64)
+
+	writeUint := func(w *bytes.Buffer, n int) {
+		l := binary.PutUvarint(buf, uint64(n))
+		w.Write(buf[:l])
+	}
+
+	writeString := func(w *bytes.Buffer, s string) {
+		writeUint(w, len(s))
+		w.WriteString(s)
+	}
+
+	reset, added := e.encodeChanges(changes, func(c encodedChange) {
+		writeUint(&ops, c.action)
+		writeUint(&ops, c.nodeID)
+		writeUint(&ops, c.mask)
+
+		ids := c.ids
+		for _, field := range encodedFields {
+			if c.mask&field == 0 || field == isCompoField {
+				continue
+			}
+
+			if field == valueField {
+				writeString(&ops, c.value)
+				continue
+			}
+
+			writeUint(&ops, ids[0])
+			ids = ids[1:]
+		}
+	})
+
+	var b bytes.Buffer
+	if reset {
+		b.WriteByte(1)
+	} else {
+		b.WriteByte(0)
+	}
+
+	writeUint(&b, len(added))
+	for _, s := range added {
+		writeString(&b, s)
+	}
+
+	writeUint(&b, len(changes))
+	b.Write(ops.Bytes())
+	return b.Bytes()
+}
+
+var encodedFields = []int{
+	compoIDField,
+	typeField,
+	namespaceField,
+	keyField,
+	valueField,
+	childIDField,
+	newChildIDField,
+	isCompoField,
+	enterField,
+	leaveField,
+}
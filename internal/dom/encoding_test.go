@@ -0,0 +1,488 @@
+package dom
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Encoded struct {
+	Items []string
+}
+
+func (e *Encoded) Render() string {
+	return `
+	<div class="list">
+		<h1 title="Items">{{len .Items}} items</h1>
+		<ul>
+			{{range .Items}}
+			<li class="item" onclick="js:console.log">
+				<span class="label">{{.}}</span>
+				<dom.bar>
+			</li>
+			{{end}}
+		</ul>
+		<svg><path d="M 42.42 Z"></path></svg>
+	</div>
+	`
+}
+
+func items(n, offset int) []string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf("Item %d", i+offset)
+	}
+	return items
+}
+
+// encodedEngine returns an engine that records the changes it synchronizes
+// and the changes of a rendering of an Encoded component.
+func encodedEngine(t testing.TB, encoding Encoding) (*Engine, *Encoded, *[]interface{}) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Encoded{})
+	f.RegisterCompo(&Bar{})
+
+	var synced []interface{}
+
+	e := &Engine{
+		Factory:  f,
+		Encoding: encoding,
+		Sync: func(v interface{}) error {
+			// The engine reuses the slice of changes.
+			if changes, ok := v.([]change); ok {
+				v = append([]change(nil), changes...)
+			}
+
+			synced = append(synced, v)
+			return nil
+		},
+	}
+
+	c := &Encoded{Items: items(10, 0)}
+	require.NoError(t, e.New(c))
+	return e, c, &synced
+}
+
+func TestEngineEncoding(t *testing.T) {
+	tests := []struct {
+		scenario string
+		encoding Encoding
+		decode   func(*stringTable, []byte) ([]change, error)
+	}{
+		{
+			scenario: "compact",
+			encoding: CompactEncoding,
+			decode:   decodeCompact,
+		},
+		{
+			scenario: "binary",
+			encoding: BinaryEncoding,
+			decode:   decodeBinary,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			ref, refCompo, refSynced := encodedEngine(t, JSONEncoding)
+			defer ref.Close()
+
+			e, c, synced := encodedEngine(t, test.encoding)
+			defer e.Close()
+
+			refCompo.Items = items(12, 3)
+			require.NoError(t, ref.Render(refCompo))
+
+			c.Items = items(12, 3)
+			require.NoError(t, e.Render(c))
+
+			require.Len(t, *synced, len(*refSynced))
+
+			var table stringTable
+
+			for i, v := range *synced {
+				b, ok := v.([]byte)
+				require.True(t, ok)
+
+				changes, err := test.decode(&table, b)
+				require.NoError(t, err)
+
+				// Node ids are generated and differ between engines.
+				expected := (*refSynced)[i].([]change)
+				require.Len(t, changes, len(expected))
+
+				for j := range changes {
+					assert.Equal(t, expected[j].Action, changes[j].Action)
+					assert.Equal(t, expected[j].Type, changes[j].Type)
+					assert.Equal(t, expected[j].Namespace, changes[j].Namespace)
+					assert.Equal(t, expected[j].Key, changes[j].Key)
+					assert.Equal(t, expected[j].Value, changes[j].Value)
+					assert.Equal(t, expected[j].IsCompo, changes[j].IsCompo)
+					assert.Equal(t, len(expected[j].CompoID) != 0, len(changes[j].CompoID) != 0)
+					assert.Equal(t, len(expected[j].ChildID) != 0, len(changes[j].ChildID) != 0)
+					assert.NotEmpty(t, changes[j].NodeID)
+				}
+			}
+
+			assert.Equal(t, 1, table.resets)
+		})
+	}
+}
+
+func TestChangeEncoder(t *testing.T) {
+	changes := []change{
+		{Action: newNode, NodeID: "div:1", Type: "div"},
+		{Action: setAttr, NodeID: "div:1", Key: "class", Value: `a "quoted" été`},
+		{Action: newNode, NodeID: "dom.bar:2", Type: "dom.bar", IsCompo: true},
+		{Action: appendChild, NodeID: "div:1", ChildID: "dom.bar:2", Enter: "fade"},
+		{Action: setText, NodeID: "text:3"},
+	}
+
+	tests := []struct {
+		scenario string
+		encode   func(*changeEncoder, []change) []byte
+		decode   func(*stringTable, []byte) ([]change, error)
+	}{
+		{
+			scenario: "compact",
+			encode:   (*changeEncoder).Compact,
+			decode:   decodeCompact,
+		},
+		{
+			scenario: "binary",
+			encode:   (*changeEncoder).Binary,
+			decode:   decodeBinary,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			var enc changeEncoder
+			var table stringTable
+
+			b := test.encode(&enc, changes)
+			decoded, err := test.decode(&table, b)
+			require.NoError(t, err)
+			assert.Equal(t, changes, decoded)
+			assert.Equal(t, 1, table.resets)
+
+			// Strings are interned once.
+			again := test.encode(&enc, changes)
+			assert.True(t, len(again) < len(b))
+
+			decoded, err = test.decode(&table, again)
+			require.NoError(t, err)
+			assert.Equal(t, changes, decoded)
+			assert.Equal(t, 1, table.resets)
+
+			// Reset clears the table on both sides.
+			enc.Reset()
+			decoded, err = test.decode(&table, test.encode(&enc, changes))
+			require.NoError(t, err)
+			assert.Equal(t, changes, decoded)
+			assert.Equal(t, 2, table.resets)
+		})
+	}
+}
+
+func TestEngineEncodingResetOnSyncError(t *testing.T) {
+	fail := false
+
+	var synced [][]byte
+
+	f := app.NewFactory()
+	f.RegisterCompo(&Encoded{})
+	f.RegisterCompo(&Bar{})
+
+	e := &Engine{
+		Factory:  f,
+		Encoding: BinaryEncoding,
+		Sync: func(v interface{}) error {
+			if fail {
+				return fmt.Errorf("simulated err")
+			}
+			synced = append(synced, v.([]byte))
+			return nil
+		},
+	}
+	defer e.Close()
+
+	c := &Encoded{Items: items(2, 0)}
+	require.NoError(t, e.New(c))
+
+	fail = true
+	c.Items = items(3, 0)
+	assert.Error(t, e.Render(c))
+
+	fail = false
+	c.Items = items(4, 0)
+	require.NoError(t, e.Render(c))
+
+	require.Len(t, synced, 2)
+	assert.Equal(t, byte(1), synced[1][0])
+}
+
+func BenchmarkEncoding(b *testing.B) {
+	ref, _, synced := encodedEngine(b, JSONEncoding)
+	defer ref.Close()
+
+	changes := (*synced)[0].([]change)
+
+	b.Run("json", func(b *testing.B) {
+		b.ReportAllocs()
+		size := 0
+
+		for n := 0; n < b.N; n++ {
+			data, err := json.Marshal(changes)
+			if err != nil {
+				b.Fatal(err)
+			}
+			size = len(data)
+		}
+
+		b.ReportMetric(float64(size), "payload-bytes")
+	})
+
+	b.Run("compact", func(b *testing.B) {
+		b.ReportAllocs()
+		size := 0
+
+		for n := 0; n < b.N; n++ {
+			var enc changeEncoder
+			size = len(enc.Compact(changes))
+		}
+
+		b.ReportMetric(float64(size), "payload-bytes")
+	})
+
+	b.Run("binary", func(b *testing.B) {
+		b.ReportAllocs()
+		size := 0
+
+		for n := 0; n < b.N; n++ {
+			var enc changeEncoder
+			size = len(enc.Binary(changes))
+		}
+
+		b.ReportMetric(float64(size), "payload-bytes")
+	})
+}
+
+func BenchmarkEngineRenderEncoding(b *testing.B) {
+	for _, encoding := range []struct {
+		name     string
+		encoding Encoding
+	}{
+		{name: "json", encoding: JSONEncoding},
+		{name: "compact", encoding: CompactEncoding},
+		{name: "binary", encoding: BinaryEncoding},
+	} {
+		b.Run(encoding.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			e, c, synced := encodedEngine(b, encoding.encoding)
+			defer e.Close()
+
+			size := 0
+			e.Sync = func(v interface{}) error {
+				if data, ok := v.([]byte); ok {
+					size += len(data)
+					return nil
+				}
+
+				data, err := json.Marshal(v)
+				size += len(data)
+				return err
+			}
+			*synced = nil
+
+			for n := 0; n < b.N; n++ {
+				c.Items = items(10, n%2)
+				if err := e.Render(c); err != nil {
+					b.Fatal(err)
+				}
+			}
+
+			b.ReportMetric(float64(size)/float64(b.N), "payload-bytes/op")
+		})
+	}
+}
+
+// stringTable is the interning table of a remote dom.
+type stringTable struct {
+	strings []string
+	resets  int
+}
+
+func (t *stringTable) intern(reset bool, strings []string) {
+	if reset {
+		t.strings = nil
+		t.resets++
+	}
+	t.strings = append(t.strings, strings...)
+}
+
+func (t *stringTable) get(i int) (string, error) {
+	if i < 0 || i >= len(t.strings) {
+		return "", fmt.Errorf("string %d is not interned", i)
+	}
+	return t.strings[i], nil
+}
+
+func (t *stringTable) decodeChange(action, nodeID, mask int, read func(field int) (string, error)) (change, error) {
+	id, err := t.get(nodeID)
+	if err != nil {
+		return change{}, err
+	}
+
+	c := change{
+		Action: changeAction(action),
+		NodeID: id,
+	}
+
+	fields := map[int]*string{
+		compoIDField:    &c.CompoID,
+		typeField:       &c.Type,
+		namespaceField:  &c.Namespace,
+		keyField:        &c.Key,
+		valueField:      &c.Value,
+		childIDField:    &c.ChildID,
+		newChildIDField: &c.NewChildID,
+		enterField:      &c.Enter,
+		leaveField:      &c.Leave,
+	}
+
+	for _, field := range encodedFields {
+		if mask&field == 0 {
+			continue
+		}
+
+		if field == isCompoField {
+			c.IsCompo = true
+			continue
+		}
+
+		if *fields[field], err = read(field); err != nil {
+			return change{}, err
+		}
+	}
+
+	return c, nil
+}
+
+func decodeCompact(t *stringTable, data []byte) ([]change, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	if len(raw) < 2 {
+		return nil, fmt.Errorf("missing header")
+	}
+
+	var reset int
+	var strings []string
+
+	if err := json.Unmarshal(raw[0], &reset); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw[1], &strings); err != nil {
+		return nil, err
+	}
+	t.intern(reset == 1, strings)
+
+	changes := make([]change, 0, len(raw)-2)
+
+	for _, r := range raw[2:] {
+		var values []json.RawMessage
+		if err := json.Unmarshal(r, &values); err != nil {
+			return nil, err
+		}
+
+		header := make([]int, 3)
+		for i := range header {
+			if err := json.Unmarshal(values[i], &header[i]); err != nil {
+				return nil, err
+			}
+		}
+		values = values[3:]
+
+		c, err := t.decodeChange(header[0], header[1], header[2], func(field int) (string, error) {
+			v := values[0]
+			values = values[1:]
+
+			if field == valueField {
+				var s string
+				err := json.Unmarshal(v, &s)
+				return s, err
+			}
+
+			var i int
+			if err := json.Unmarshal(v, &i); err != nil {
+				return "", err
+			}
+			return t.get(i)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, c)
+	}
+
+	return changes, nil
+}
+
+func decodeBinary(t *stringTable, data []byte) ([]change, error) {
+	r := bytes.NewReader(data)
+
+	readUint := func() int {
+		n, _ := binary.ReadUvarint(r)
+		return int(n)
+	}
+
+	readString := func() string {
+		b := make([]byte, readUint())
+		r.Read(b)
+		return string(b)
+	}
+
+	reset, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	strings := make([]string, readUint())
+	for i := range strings {
+		strings[i] = readString()
+	}
+	t.intern(reset == 1, strings)
+
+	changes := make([]change, readUint())
+
+	for i := range changes {
+		action := readUint()
+		nodeID := readUint()
+		mask := readUint()
+
+		if changes[i], err = t.decodeChange(action, nodeID, mask, func(field int) (string, error) {
+			if field == valueField {
+				return readString(), nil
+			}
+			return t.get(readUint())
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("%d bytes left", r.Len())
+	}
+
+	return changes, nil
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/murlokswarm/app"
 	"github.com/pkg/errors"
@@ -16,6 +17,19 @@ import (
 // Engine represents a dom (document object model) engine.
 // It manages components an nodes lifecycle and keep track of node changes.
 // The engine can be synchronized with a remote dom like a web browser document.
+//
+// Nodes of type portal are displayed in another location of the remote dom:
+// the node that matches the CSS selector in their target attribute, or the
+// document body when there is none. Their children still belong to the
+// component that renders them, so their events are routed to it.
+//
+// Nodes with a transition attribute are animated when they are inserted or
+// removed from the remote dom. The class named after the attribute value with
+// the -enter suffix is set on inserted nodes and removed on the next frame.
+// The class with the -leave suffix is set on removed nodes that are kept in
+// the remote dom until their CSS transition or animation ends. Components are
+// dismounted without waiting for the transition.
+//
 // It is safe for concurrent operations.
 type Engine struct {
 	// The factory to decode component from html.
@@ -32,15 +46,41 @@ type Engine struct {
 	// All node type is allowed when the slice is empty.
 	AllowedNodes []string
 
+	// Sanitize describes how the markup rendered by components is sanitized.
+	// Nothing is sanitized when it is the zero value.
+	Sanitize app.SanitizeConfig
+
+	// Profile is the function called with the profile of each rendering.
+	// If the func is nil, renderings are profiled only when app.Profiling
+	// reports true and profiles are reported with app.ReportRenderProfile.
+	Profile func(p app.RenderProfile)
+
 	// Sync is the function used to synchronize node changes with a remote dom.
 	// No synchronisations are performed if the func in nil.
 	Sync func(arg interface{}) error
 
+	// Encoding is the encoding of the node changes passed to Sync.
+	// Changes are passed as they are with JSONEncoding, the default, and as a
+	// []byte with the other encodings.
+	Encoding Encoding
+
+	// ReportErr is the function called with the errors that are not returned
+	// by the engine methods, like the ones caught by error boundaries or the
+	// ones that occur when rendering asynchronously loaded components.
+	// Errors are logged if the func is nil.
+	ReportErr func(err error)
+
+	// CallOnUIGoroutine is the function used to apply the results of
+	// asynchronous loads on the UI goroutine.
+	// app.CallOnUIGoroutine is used if the func is nil.
+	CallOnUIGoroutine func(func())
+
 	once          sync.Once
 	mutex         sync.RWMutex
 	compos        map[app.Compo]compo
 	compoIDs      map[string]compo
 	nodes         map[string]node
+	styles        map[string]int
 	allowdedNodes map[string]struct{}
 	rootID        string
 	creates       []change
@@ -48,6 +88,13 @@ type Engine struct {
 	deletes       []change
 	toSync        []change
 	decodeAttrs   map[string]string
+	orphans       bool
+	localeEvents  *app.EventSubscriber
+	profile       *app.RenderProfile
+	encoder       changeEncoder
+	restoring     map[string][]*nodeSnapshot
+	rawPolicy     *Policy
+	policy        *Policy
 }
 
 func (e *Engine) init() {
@@ -57,9 +104,30 @@ func (e *Engine) init() {
 		}
 	}
 
+	if e.ReportErr == nil {
+		e.ReportErr = func(err error) {
+			if app.Logger != nil {
+				app.Logf("%+v", err)
+			}
+		}
+	}
+
+	if e.CallOnUIGoroutine == nil {
+		e.CallOnUIGoroutine = app.CallOnUIGoroutine
+	}
+
+	if e.Sanitize.Raw != nil {
+		e.rawPolicy = NewPolicy(*e.Sanitize.Raw)
+	}
+
+	if e.Sanitize.Markup != nil {
+		e.policy = NewPolicy(*e.Sanitize.Markup)
+	}
+
 	e.compos = make(map[app.Compo]compo)
 	e.compoIDs = make(map[string]compo)
 	e.nodes = make(map[string]node)
+	e.styles = make(map[string]int)
 
 	if len(e.AllowedNodes) != 0 {
 		e.allowdedNodes = make(map[string]struct{}, len(e.AllowedNodes))
@@ -105,21 +173,31 @@ func (e *Engine) New(c app.Compo) error {
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
 
+	return e.newRoot(c)
+}
+
+func (e *Engine) newRoot(c app.Compo) error {
 	e.close()
 
-	if err := e.render(c); err != nil {
-		return err
+	if e.localeEvents == nil {
+		e.localeEvents = app.NewEventSubscriber().
+			Subscribe(app.LocaleChanged, e.onLocaleChange)
 	}
 
-	ic := e.compos[c]
-	e.rootID = ic.ID
+	e.startProfile(c)
 
-	e.changes = append(e.changes, change{
-		Action: setRoot,
-		NodeID: ic.ID,
-	})
+	err := e.render(c)
+	if err == nil {
+		ic := e.compos[c]
+		e.rootID = ic.ID
 
-	return e.sync()
+		e.changes = append(e.changes, change{
+			Action: setRoot,
+			NodeID: ic.ID,
+		})
+	}
+
+	return e.syncAfter(err)
 }
 
 // Close deletes the components and nodes from the dom.
@@ -129,6 +207,11 @@ func (e *Engine) Close() {
 	defer e.mutex.Unlock()
 
 	e.close()
+
+	if e.localeEvents != nil {
+		e.localeEvents.Close()
+		e.localeEvents = nil
+	}
 }
 
 func (e *Engine) close() {
@@ -147,10 +230,15 @@ func (e *Engine) close() {
 		delete(e.nodes, k)
 	}
 
+	for k := range e.styles {
+		delete(e.styles, k)
+	}
+
 	e.creates = clearChanges(e.creates)
 	e.changes = clearChanges(e.changes)
 	e.deletes = clearChanges(e.deletes)
 	e.toSync = clearChanges(e.toSync)
+	e.encoder.Reset()
 }
 
 // Render renders the given component by updating the state described within
@@ -164,16 +252,65 @@ func (e *Engine) Render(c app.Compo) error {
 		return app.ErrCompoNotMounted
 	}
 
-	if err := e.render(c); err != nil {
-		return err
+	e.startProfile(c)
+	return e.syncAfter(e.renderBounded(c))
+}
+
+// onLocaleChange renders all the components again to display them in the
+// new locale.
+func (e *Engine) onLocaleChange(locale string) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	compos := make([]app.Compo, 0, len(e.compos))
+	for c := range e.compos {
+		compos = append(compos, c)
+	}
+
+	var err error
+
+	if root, ok := e.compoIDs[e.rootID]; ok {
+		e.startProfile(root.Compo)
+	}
+
+	for _, c := range compos {
+		// The component may have been dismounted by the rendering of its
+		// parent.
+		if _, ok := e.compos[c]; !ok {
+			continue
+		}
+
+		if err = e.renderBounded(c); err != nil {
+			break
+		}
 	}
 
-	return e.sync()
+	if err = e.syncAfter(err); err != nil {
+		e.ReportErr(err)
+	}
+}
+
+// syncAfter synchronizes the changes of a rendering that may have failed.
+// Nodes left by failed renderings are deleted beforehand so the remote dom
+// stays consistent and usable.
+func (e *Engine) syncAfter(err error) error {
+	if e.orphans {
+		e.deleteOrphans()
+	}
+
+	if serr := e.sync(); err == nil {
+		err = serr
+	}
+
+	e.endProfile()
+	return err
 }
 
+// render renders the given component. When the component is an error
+// boundary, it catches the failures of its descendants and is rendered again
+// to display a fallback.
 func (e *Engine) render(c app.Compo) error {
-	ic, ok := e.compos[c]
-	if !ok {
+	if _, ok := e.compos[c]; !ok {
 		typ := app.CompoName(c)
 
 		if err := e.newCompo(c, node{
@@ -182,12 +319,80 @@ func (e *Engine) render(c app.Compo) error {
 			ChildIDs: make([]string, 1),
 			IsCompo:  true,
 		}); err != nil {
-			return err
+			e.orphans = true
+			return compoErr(c, err)
 		}
+	}
 
-		ic = e.compos[c]
+	fromDescendant, err := e.renderCompo(c)
+	if err == nil {
+		return nil
 	}
 
+	e.orphans = true
+
+	boundary, ok := c.(app.ErrorBoundary)
+	if !fromDescendant || !ok {
+		return compoErr(c, err)
+	}
+
+	e.ReportErr(errors.Wrapf(err, "%s caught an error", app.CompoName(c)))
+	boundary.OnError(errors.Cause(err).(app.CompoError))
+
+	if _, err = e.renderCompo(c); err != nil {
+		return compoErr(c, err)
+	}
+
+	return nil
+}
+
+// renderBounded renders the given mounted component. Failures are caught by
+// the nearest error boundary among its ancestors, which is rendered again to
+// display a fallback.
+func (e *Engine) renderBounded(c app.Compo) error {
+	boundary, ok := e.boundaryOf(c)
+
+	err := e.render(c)
+	if err == nil || !ok {
+		return err
+	}
+
+	e.ReportErr(errors.Wrapf(err, "%s caught an error", app.CompoName(boundary)))
+	boundary.OnError(errors.Cause(err).(app.CompoError))
+	return e.renderBounded(boundary)
+}
+
+// boundaryOf returns the nearest mounted error boundary among the ancestors
+// of the given component.
+func (e *Engine) boundaryOf(c app.Compo) (app.ErrorBoundary, bool) {
+	for id := e.compos[c].ParentID; len(id) != 0; {
+		parent, ok := e.compoIDs[id]
+		if !ok {
+			return nil, false
+		}
+
+		if boundary, ok := parent.Compo.(app.ErrorBoundary); ok {
+			return boundary, true
+		}
+
+		id = parent.ParentID
+	}
+
+	return nil, false
+}
+
+// renderCompo renders the markup of the given mounted component. It reports
+// whether an error comes from the failure of a descendant component.
+func (e *Engine) renderCompo(c app.Compo) (fromDescendant bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			fromDescendant = false
+			err = compoPanic(c, r)
+		}
+	}()
+
+	ic := e.compos[c]
+
 	n := e.nodes[ic.ID]
 	root := node{}
 	newRoot := node{}
@@ -196,9 +401,11 @@ func (e *Engine) render(c app.Compo) error {
 		root = e.nodes[n.ChildIDs[0]]
 	}
 
+	app.ValidateMounted(c)
+
 	markup, err := e.compoToHTML(c)
 	if err != nil {
-		return errors.Wrap(err, "reading component failed")
+		return false, errors.Wrap(err, "reading component failed")
 	}
 
 	if newRoot, _, err = e.renderNode(rendering{
@@ -206,7 +413,7 @@ func (e *Engine) render(c app.Compo) error {
 		CompoID:    n.ID,
 		NodeToSync: root,
 	}); err != nil {
-		return err
+		return isCompoErr(err), err
 	}
 
 	n.ChildIDs[0] = newRoot.ID
@@ -214,23 +421,14 @@ func (e *Engine) render(c app.Compo) error {
 
 	switch {
 	case len(root.ID) == 0:
-		e.changes = append(e.changes, change{
-			Action:  appendChild,
-			NodeID:  n.ID,
-			ChildID: newRoot.ID,
-		})
+		e.appendChildChange(n.ID, newRoot.ID)
 
 	case root.ID != newRoot.ID:
+		e.replaceChildChange(n.ID, root.ID, newRoot.ID)
 		e.deleteNode(root.ID)
-		e.changes = append(e.changes, change{
-			Action:     replaceChild,
-			NodeID:     n.ID,
-			ChildID:    root.ID,
-			NewChildID: newRoot.ID,
-		})
 	}
 
-	return nil
+	return false, nil
 }
 
 func (e *Engine) compoToHTML(c app.Compo) (string, error) {
@@ -240,16 +438,21 @@ func (e *Engine) compoToHTML(c app.Compo) (string, error) {
 	}
 
 	// The number of template functions. It contains the
-	// component extended functions, the converters and
-	// the resources accessor.
-	funcsCount := len(converters) + len(extendedFuncs) + 1
+	// component extended functions, the converters, the
+	// resources accessor and the loading state accessors.
+	funcsCount := len(converters) + len(extendedFuncs) + 3
+
+	load := e.compos[c].Load
 
 	funcs := make(template.FuncMap, funcsCount)
 	funcs["resources"] = e.Resources
+	funcs["loading"] = load.loading
+	funcs["loadErr"] = load.loadErr
 
 	for k, v := range converters {
 		funcs[k] = v
 	}
+	funcs["raw"] = e.rawHTML
 
 	for k, v := range extendedFuncs {
 		if _, ok := funcs[k]; ok {
@@ -258,6 +461,8 @@ func (e *Engine) compoToHTML(c app.Compo) (string, error) {
 		funcs[k] = v
 	}
 
+	defer e.profileTemplate(c, time.Now())
+
 	tmpl, err := template.
 		New(fmt.Sprintf("%T", c)).
 		Funcs(funcs).
@@ -271,7 +476,12 @@ func (e *Engine) compoToHTML(c app.Compo) (string, error) {
 		return "", err
 	}
 
-	html := strings.TrimSpace(w.String())
+	html := w.String()
+	if e.policy != nil {
+		html = e.policy.sanitize(html, true)
+	}
+
+	html = strings.TrimSpace(html)
 	if len(html) == 0 {
 		return "", errors.New("component does not render anything")
 	}
@@ -279,6 +489,13 @@ func (e *Engine) compoToHTML(c app.Compo) (string, error) {
 	return html, nil
 }
 
+func (e *Engine) rawHTML(s string) template.HTML {
+	if e.rawPolicy == nil {
+		return template.HTML(s)
+	}
+	return template.HTML(e.rawPolicy.Sanitize(s))
+}
+
 func (e *Engine) renderNode(r rendering) (node, bool, error) {
 	switch r.Tokenizer.Next() {
 	case html.TextToken:
@@ -364,15 +581,16 @@ func (e *Engine) renderSelfClosingTag(r rendering) (node, bool, error) {
 		e.newNode(n)
 	}
 
+	prevRef := n.Attrs["ref"]
 	n = e.renderTagAttrs(r, n, hasAttr, true)
 
+	if err := e.updateRef(n, prevRef); err != nil {
+		return node{}, false, err
+	}
+
 	for _, childID := range n.ChildIDs {
+		e.removeChildChange(n.ID, childID)
 		e.deleteNode(childID)
-		e.changes = append(e.changes, change{
-			Action:  removeChild,
-			NodeID:  n.ID,
-			ChildID: childID,
-		})
 	}
 
 	n.ChildIDs = clearNodeIDs(n.ChildIDs)
@@ -409,8 +627,13 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 		e.newNode(n)
 	}
 
+	prevRef := n.Attrs["ref"]
 	n = e.renderTagAttrs(r, n, hasAttr, true)
 
+	if err := e.updateRef(n, prevRef); err != nil {
+		return node{}, false, err
+	}
+
 	if isVoidElem(n.Type) {
 		return n, true, nil
 	}
@@ -442,12 +665,7 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 		}
 
 		if new.ID != old.ID {
-			e.changes = append(e.changes, change{
-				Action:     replaceChild,
-				NodeID:     n.ID,
-				ChildID:    old.ID,
-				NewChildID: new.ID,
-			})
+			e.replaceChildChange(n.ID, old.ID, new.ID)
 
 			childIDs[0] = new.ID
 			new.ParentID = n.ID
@@ -461,13 +679,8 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 
 	// Remove children:
 	for _, childID := range childIDs {
+		e.removeChildChange(n.ID, childID)
 		e.deleteNode(childID)
-		e.changes = append(e.changes, change{
-			Action:  removeChild,
-			NodeID:  n.ID,
-			ChildID: childID,
-		})
-
 	}
 	childIDs = clearNodesIDsFrom(n.ChildIDs, count)
 
@@ -483,6 +696,9 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 		})
 
 		if err != nil {
+			// Keeps track of the children added so far.
+			n.ChildIDs = childIDs
+			e.nodes[n.ID] = n
 			return node{}, false, err
 		}
 
@@ -491,11 +707,7 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 		}
 
 		childIDs = append(childIDs, child.ID)
-		e.changes = append(e.changes, change{
-			Action:  appendChild,
-			NodeID:  n.ID,
-			ChildID: child.ID,
-		})
+		e.appendChildChange(n.ID, child.ID)
 	}
 
 	n.ChildIDs = childIDs
@@ -504,7 +716,14 @@ func (e *Engine) renderStartTag(r rendering) (node, bool, error) {
 }
 
 func (e *Engine) renderTagAttrs(r rendering, n node, moreAttr, changes bool) node {
-	if !moreAttr {
+	// Nodes rendered by a component with styles are marked with the
+	// component style scope.
+	scope := ""
+	if changes {
+		scope = e.compoIDs[r.CompoID].Scope
+	}
+
+	if !moreAttr && len(scope) == 0 {
 		return n
 	}
 
@@ -524,25 +743,19 @@ func (e *Engine) renderTagAttrs(r rendering, n node, moreAttr, changes bool) nod
 			k = svgAttr(k)
 		}
 
-		for _, t := range e.AttrTransforms {
-			k, v = t(k, v)
+		if e.Sanitize.DisableJSHandlers && isJSHandler(k, v) {
+			continue
 		}
 
-		e.decodeAttrs[k] = v
-		if currentVal, ok := n.Attrs[k]; ok && currentVal == v {
-			continue
+		for _, t := range e.AttrTransforms {
+			k, v = t(k, v)
 		}
 
-		n.Attrs[k] = v
+		e.renderAttr(n, k, v, changes)
+	}
 
-		if changes {
-			e.changes = append(e.changes, change{
-				Action: setAttr,
-				NodeID: n.ID,
-				Key:    k,
-				Value:  v,
-			})
-		}
+	if len(scope) != 0 {
+		e.renderAttr(n, scope, "", changes)
 	}
 
 	for k := range n.Attrs {
@@ -569,6 +782,24 @@ func (e *Engine) renderTagAttrs(r rendering, n node, moreAttr, changes bool) nod
 	return n
 }
 
+func (e *Engine) renderAttr(n node, k, v string, changes bool) {
+	e.decodeAttrs[k] = v
+	if currentVal, ok := n.Attrs[k]; ok && currentVal == v {
+		return
+	}
+
+	n.Attrs[k] = v
+
+	if changes {
+		e.changes = append(e.changes, change{
+			Action: setAttr,
+			NodeID: n.ID,
+			Key:    k,
+			Value:  v,
+		})
+	}
+}
+
 func (e *Engine) renderCompoNode(r rendering, typ string, hasAttr bool) (node, bool, error) {
 	n := r.NodeToSync
 
@@ -615,8 +846,7 @@ func (e *Engine) newNode(n node) {
 	})
 }
 
-func (e *Engine) newCompo(c app.Compo, n node) error {
-	var err error
+func (e *Engine) newCompo(c app.Compo, n node) (err error) {
 	if c == nil {
 		if c, err = e.Factory.NewCompo(n.Type); err != nil {
 			return err
@@ -627,24 +857,48 @@ func (e *Engine) newCompo(c app.Compo, n node) error {
 		return err
 	}
 
+	if err := e.restoreCompo(c, n); err != nil {
+		return err
+	}
+
 	e.newNode(n)
 
 	ic := compo{
-		ID:    n.ID,
-		Compo: c,
+		ID:       n.ID,
+		ParentID: n.CompoID,
+		Compo:    c,
 	}
 
 	if sub, ok := c.(app.Subscriber); ok {
 		ic.Events = sub.Subscribe()
 	}
 
+	if _, ok := c.(app.Styler); ok {
+		ic.Scope = styleScope(n.Type)
+	}
+
+	if _, ok := c.(app.Loader); ok {
+		ic.Load = &asyncLoad{pending: true}
+	}
+
 	e.compoIDs[n.ID] = ic
 	e.compos[c] = ic
+	e.mountStyles(ic)
 
 	if mounter, ok := c.(app.Mounter); ok {
+		defer func() {
+			if r := recover(); r != nil {
+				err = compoPanic(c, r)
+			}
+		}()
+
 		mounter.OnMount()
 	}
 
+	if ic.Load != nil {
+		e.startLoad(ic)
+	}
+
 	return nil
 }
 
@@ -658,12 +912,18 @@ func (e *Engine) deleteNode(id string) {
 		e.deleteNode(childID)
 	}
 
+	if ref, ok := n.Attrs["ref"]; ok && !n.IsCompo {
+		e.unbindRef(n, ref)
+	}
+
 	if n.IsCompo {
 		if c, ok := e.compoIDs[n.ID]; ok {
 			if dismounter, ok := c.Compo.(app.Dismounter); ok {
-				dismounter.OnDismount()
+				e.dismount(dismounter)
 			}
 
+			e.cancelLoad(c)
+			e.dismountStyles(c)
 			delete(e.compos, c.Compo)
 			delete(e.compoIDs, c.ID)
 		}
@@ -676,15 +936,73 @@ func (e *Engine) deleteNode(id string) {
 	})
 }
 
+func (e *Engine) dismount(d app.Dismounter) {
+	defer func() {
+		if r := recover(); r != nil {
+			e.ReportErr(compoPanic(d, r))
+		}
+	}()
+
+	d.OnDismount()
+}
+
+// deleteOrphans deletes the nodes that are not attached to the root node.
+func (e *Engine) deleteOrphans() {
+	attached := make(map[string]struct{}, len(e.nodes))
+	e.markAttached(e.rootID, attached)
+
+	for id := range e.nodes {
+		if _, ok := attached[id]; !ok {
+			e.deleteNode(id)
+		}
+	}
+
+	e.orphans = false
+}
+
+func (e *Engine) markAttached(id string, attached map[string]struct{}) {
+	n, ok := e.nodes[id]
+	if !ok {
+		return
+	}
+
+	attached[id] = struct{}{}
+
+	for _, childID := range n.ChildIDs {
+		e.markAttached(childID, attached)
+	}
+}
+
 func (e *Engine) sync() error {
 	e.toSync = append(e.toSync, e.creates...)
 	e.toSync = append(e.toSync, e.changes...)
 	e.toSync = append(e.toSync, e.deletes...)
 
-	if err := e.Sync(e.toSync); err != nil {
+	var changes interface{} = e.toSync
+
+	switch e.Encoding {
+	case CompactEncoding:
+		changes = e.encoder.Compact(e.toSync)
+
+	case BinaryEncoding:
+		changes = e.encoder.Binary(e.toSync)
+	}
+
+	if e.profile != nil {
+		e.profileSync(changes)
+	}
+
+	start := time.Now()
+	if err := e.Sync(changes); err != nil {
+		// The remote dom may not have interned the new strings.
+		e.encoder.Reset()
 		return errors.Wrap(err, "syncing dom failed")
 	}
 
+	if e.profile != nil {
+		e.profile.SyncDuration = time.Since(start)
+	}
+
 	e.creates = clearChanges(e.creates)
 	e.changes = clearChanges(e.changes)
 	e.deletes = clearChanges(e.deletes)
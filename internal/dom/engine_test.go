@@ -910,6 +910,234 @@ func TestDOMCompoByID(t *testing.T) {
 	require.Error(t, err)
 }
 
+type Modal struct {
+	Open bool
+}
+
+func (m *Modal) Render() string {
+	return `
+	<div>
+		{{if .Open}}
+			<portal target="#modals">
+				<button onclick="Close">close</button>
+			</portal>
+		{{end}}
+	</div>
+	`
+}
+
+func (m *Modal) Close() {
+	m.Open = false
+}
+
+func TestEnginePortal(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Modal{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	m := &Modal{Open: true}
+	err := e.New(m)
+	require.NoError(t, err)
+
+	compoID := e.compos[m].ID
+	var portal node
+
+	for _, n := range e.nodes {
+		if n.Type == "portal" {
+			portal = n
+		}
+	}
+
+	require.NotEmpty(t, portal.ID)
+	assert.Equal(t, "#modals", portal.Attrs["target"])
+	require.Len(t, portal.ChildIDs, 1)
+
+	button := e.nodes[portal.ChildIDs[0]]
+	assert.Equal(t, "button", button.Type)
+	assert.Equal(t, compoID, button.CompoID)
+
+	m.Open = false
+	err = e.Render(m)
+	require.NoError(t, err)
+	assert.NotContains(t, e.nodes, portal.ID)
+	assert.NotContains(t, e.nodes, button.ID)
+}
+
+type ListRow struct {
+	Index  int
+	Source string
+}
+
+func (r *ListRow) Render() string {
+	return `<p>{{.Source}} {{.Index}}</p>`
+}
+
+func TestEngineVirtualList(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&app.VirtualList{})
+	f.RegisterCompo(&ListRow{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	l := &app.VirtualList{
+		Row:       "dom.listrow",
+		Source:    "rows",
+		Count:     50000,
+		RowHeight: 20,
+		Height:    200,
+	}
+
+	err := e.New(l)
+	require.NoError(t, err)
+	assert.True(t, len(e.nodes) < 100)
+
+	rows := make(map[app.Compo]struct{})
+	for c := range e.compos {
+		if r, ok := c.(*ListRow); ok {
+			assert.Equal(t, "rows", r.Source)
+			rows[r] = struct{}{}
+		}
+	}
+	assert.Len(t, rows, len(l.Rows()))
+
+	l.Height = 400
+	err = e.Render(l)
+	require.NoError(t, err)
+
+	reused := 0
+	for c := range e.compos {
+		if _, ok := rows[c]; ok {
+			reused++
+		}
+	}
+	assert.Len(t, rows, reused)
+}
+
+type Translated struct {
+	Count int
+}
+
+func (t *Translated) Render() string {
+	return `
+	<div dir="{{dir}}">
+		<h1>{{t "title"}}</h1>
+		<dom.translateditems count="{{.Count}}">
+	</div>
+	`
+}
+
+type TranslatedItems struct {
+	Count int
+}
+
+func (t *TranslatedItems) Render() string {
+	return `<p>{{plural "items" .Count (number .Count)}}</p>`
+}
+
+func TestEngineLocaleChange(t *testing.T) {
+	locale := app.Locale()
+	defer app.SetLocale(locale)
+
+	err := app.LoadCatalog("en", []byte(`{
+		"title": "Cart",
+		"items": {"one": "%s item", "other": "%s items"}
+	}`))
+	require.NoError(t, err)
+
+	err = app.LoadCatalog("fr", []byte(`{
+		"title": "Panier",
+		"items": {"one": "%s article", "other": "%s articles"}
+	}`))
+	require.NoError(t, err)
+
+	f := app.NewFactory()
+	f.RegisterCompo(&Translated{})
+	f.RegisterCompo(&TranslatedItems{})
+
+	var changes []change
+
+	e := Engine{
+		Factory: f,
+		Sync: func(v interface{}) error {
+			changes = v.([]change)
+			return nil
+		},
+	}
+	defer e.Close()
+
+	app.SetLocale("en")
+
+	err = e.New(&Translated{Count: 1200})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Cart", "1,200 items"}, texts(&e))
+
+	app.SetLocale("fr")
+	e.onLocaleChange("fr")
+	assert.ElementsMatch(t, []string{"Panier", "1\u00a0200 articles"}, texts(&e))
+
+	for _, c := range changes {
+		assert.NotEqual(t, newNode, c.Action)
+	}
+}
+
+type Helpers struct {
+	Busy  bool
+	Color string
+	Name  string
+}
+
+func (h *Helpers) Render() string {
+	return `
+	<a href="{{url "dom.helpers" "name" .Name}}"
+	   class="{{classes "link" (.States)}}"
+	   style="{{style "color" .Color "width" "42px"}}"
+	   {{attr "disabled" .Busy}}>
+		{{.Name | default "Anonymous" | truncate 6}}
+	</a>
+	`
+}
+
+func (h *Helpers) States() map[string]bool {
+	return map[string]bool{"busy": h.Busy}
+}
+
+func TestEngineTemplateHelpers(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Helpers{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	err := e.New(&Helpers{
+		Busy:  true,
+		Color: "red",
+		Name:  "Maxence",
+	})
+	require.NoError(t, err)
+
+	a := e.nodes[e.nodes[e.rootID].ChildIDs[0]]
+	assert.Equal(t, "dom.helpers?name=Maxence", a.Attrs["href"])
+	assert.Equal(t, "link busy", a.Attrs["class"])
+	assert.Equal(t, "color: red; width: 42px", a.Attrs["style"])
+	assert.Contains(t, a.Attrs, "disabled")
+	assert.ElementsMatch(t, []string{"Maxen…"}, texts(&e))
+}
+
+func texts(e *Engine) []string {
+	var texts []string
+
+	for _, n := range e.nodes {
+		if n.Type == "text" {
+			texts = append(texts, n.Text)
+		}
+	}
+
+	return texts
+}
+
 func pretty(v interface{}) string {
 	s, _ := json.MarshalIndent(v, "", "    ")
 	return string(s)
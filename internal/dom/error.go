@@ -0,0 +1,36 @@
+package dom
+
+import (
+	"runtime/debug"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+)
+
+// compoErr returns the given error as a component error. Errors that already
+// are component errors are returned unchanged.
+func compoErr(c app.Compo, err error) error {
+	if isCompoErr(err) {
+		return err
+	}
+
+	return app.CompoError{
+		Compo: app.CompoName(c),
+		Err:   err,
+	}
+}
+
+// compoPanic returns a component error that describes the given recovered
+// panic.
+func compoPanic(c app.Compo, r interface{}) error {
+	return app.CompoError{
+		Compo: app.CompoName(c),
+		Err:   errors.Errorf("panic: %v", r),
+		Stack: string(debug.Stack()),
+	}
+}
+
+func isCompoErr(err error) bool {
+	_, ok := errors.Cause(err).(app.CompoError)
+	return ok
+}
@@ -0,0 +1,248 @@
+package dom
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Faulty struct {
+	PanicOnRender bool
+	PanicOnMount  bool
+	TemplateErr   bool
+}
+
+func (f *Faulty) OnMount() {
+	if f.PanicOnMount {
+		panic("mount")
+	}
+}
+
+func (f *Faulty) Render() string {
+	if f.PanicOnRender {
+		panic("render")
+	}
+
+	return `
+	<p>
+		{{if .TemplateErr}}
+			{{.Unknown}}
+		{{end}}
+		faulty
+	</p>
+	`
+}
+
+func (f *Faulty) Click() {
+	panic("click")
+}
+
+type MountFaulty app.ZeroCompo
+
+func (m *MountFaulty) OnMount() {
+	panic("mount")
+}
+
+func (m *MountFaulty) Render() string {
+	return `<p>mount faulty</p>`
+}
+
+type Boundary struct {
+	Child      string
+	MountPanic bool
+	Err        app.CompoError
+}
+
+func (b *Boundary) OnError(err app.CompoError) {
+	b.Err = err
+}
+
+func (b *Boundary) Render() string {
+	return `
+	<div>
+		{{if .Err.Err}}
+			<p>fallback</p>
+		{{else if .MountPanic}}
+			<dom.mountfaulty>
+		{{else}}
+			<dom.faulty {{.Child}}>
+		{{end}}
+	</div>
+	`
+}
+
+type Unbounded struct {
+	Child string
+}
+
+func (u *Unbounded) Render() string {
+	return `
+	<div>
+		<h1>title</h1>
+		<dom.faulty {{.Child}}>
+	</div>
+	`
+}
+
+func TestEngineErrorBoundary(t *testing.T) {
+	tests := []struct {
+		scenario   string
+		child      string
+		mountPanic bool
+		failing    string
+	}{
+		{
+			scenario: "render panic",
+			child:    "panicOnRender",
+			failing:  "dom.faulty",
+		},
+		{
+			scenario:   "mount panic",
+			mountPanic: true,
+			failing:    "dom.mountfaulty",
+		},
+		{
+			scenario: "template error",
+			child:    "templateErr",
+			failing:  "dom.faulty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			f := app.NewFactory()
+			f.RegisterCompo(&Faulty{})
+			f.RegisterCompo(&MountFaulty{})
+			f.RegisterCompo(&Boundary{})
+
+			var caught error
+
+			e := Engine{
+				Factory: f,
+				ReportErr: func(err error) {
+					caught = err
+				},
+			}
+			defer e.Close()
+
+			c := &Boundary{}
+			err := e.New(c)
+			require.NoError(t, err)
+			assert.Nil(t, c.Err.Err)
+
+			c.Child = test.child
+			c.MountPanic = test.mountPanic
+			err = e.Render(c)
+			require.NoError(t, err)
+			require.Error(t, caught)
+			assert.Equal(t, test.failing, c.Err.Compo)
+
+			for _, n := range e.nodes {
+				assert.NotEqual(t, test.failing, n.Type)
+			}
+
+			assert.Len(t, e.compos, 1)
+		})
+	}
+}
+
+func TestEngineErrorBoundaryDescendantRender(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Faulty{})
+	f.RegisterCompo(&Boundary{})
+
+	var caught error
+
+	e := Engine{
+		Factory: f,
+		ReportErr: func(err error) {
+			caught = err
+		},
+	}
+	defer e.Close()
+
+	c := &Boundary{}
+	err := e.New(c)
+	require.NoError(t, err)
+
+	var child *Faulty
+	for compo := range e.compos {
+		if faulty, ok := compo.(*Faulty); ok {
+			child = faulty
+		}
+	}
+	require.NotNil(t, child)
+
+	child.PanicOnRender = true
+	err = e.Render(child)
+	require.NoError(t, err)
+	require.Error(t, caught)
+	assert.Equal(t, "dom.faulty", c.Err.Compo)
+
+	for _, n := range e.nodes {
+		assert.NotEqual(t, "dom.faulty", n.Type)
+	}
+
+	assert.Len(t, e.compos, 1)
+}
+
+func TestEngineUncaughtError(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Faulty{})
+	f.RegisterCompo(&Unbounded{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	c := &Unbounded{}
+	err := e.New(c)
+	require.NoError(t, err)
+	nodeCount := len(e.nodes)
+
+	c.Child = "panicOnRender"
+	err = e.Render(c)
+	require.Error(t, err)
+
+	compoErr, ok := errors.Cause(err).(app.CompoError)
+	require.True(t, ok)
+	assert.Equal(t, "dom.faulty", compoErr.Compo)
+	assert.NotEmpty(t, compoErr.Stack)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "goroutine")
+
+	c.Child = ""
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.Len(t, e.nodes, nodeCount)
+}
+
+func TestEngineNewWithError(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Faulty{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	err := e.New(&Faulty{PanicOnMount: true})
+	require.Error(t, err)
+	assert.Empty(t, e.nodes)
+	assert.Empty(t, e.compos)
+}
+
+func TestMappingPanic(t *testing.T) {
+	m := Mapping{FieldOrMethod: "Click"}
+
+	f, err := m.Map(&Faulty{})
+	require.NoError(t, err)
+
+	err = f()
+	require.Error(t, err)
+
+	compoErr, ok := err.(app.CompoError)
+	require.True(t, ok)
+	assert.Equal(t, "dom.faulty", compoErr.Compo)
+	assert.NotEmpty(t, compoErr.Stack)
+}
@@ -0,0 +1,122 @@
+package dom
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/murlokswarm/app"
+	"golang.org/x/net/html"
+)
+
+// HTML returns the markup of the root component. It is used to render pages
+// on the server side.
+// Event handlers are removed since they require the client runtime. Portals
+// are not rendered because their location is resolved by the client.
+func (e *Engine) HTML() string {
+	e.once.Do(e.init)
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	var b bytes.Buffer
+	e.nodeHTML(&b, e.rootID)
+	return b.String()
+}
+
+func (e *Engine) nodeHTML(b *bytes.Buffer, id string) {
+	n, ok := e.nodes[id]
+	if !ok {
+		return
+	}
+
+	switch {
+	case n.IsCompo:
+		for _, childID := range n.ChildIDs {
+			e.nodeHTML(b, childID)
+		}
+		return
+
+	case n.Type == "text":
+		b.WriteString(html.EscapeString(n.Text))
+		return
+
+	case n.Type == portal:
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(n.Type)
+
+	if len(n.Namespace) != 0 && n.Type == "svg" {
+		b.WriteString(` xmlns="`)
+		b.WriteString(n.Namespace)
+		b.WriteByte('"')
+	}
+
+	keys := make([]string, 0, len(n.Attrs))
+	for k := range n.Attrs {
+		if !strings.HasPrefix(k, "on") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(k)
+
+		if v := n.Attrs[k]; len(v) != 0 {
+			b.WriteString(`="`)
+			b.WriteString(html.EscapeString(v))
+			b.WriteByte('"')
+		}
+	}
+
+	b.WriteByte('>')
+
+	if len(n.Namespace) == 0 && isVoidElem(n.Type) {
+		return
+	}
+
+	if len(n.Namespace) == 0 && isRawTextElem(n.Type) {
+		e.rawTextHTML(b, n)
+	} else {
+		for _, childID := range n.ChildIDs {
+			e.nodeHTML(b, childID)
+		}
+	}
+
+	b.WriteString("</")
+	b.WriteString(n.Type)
+	b.WriteByte('>')
+}
+
+// rawTextHTML writes the text content of elements like script or style
+// without escaping it, as browsers do not decode entities within them.
+func (e *Engine) rawTextHTML(b *bytes.Buffer, n node) {
+	for _, childID := range n.ChildIDs {
+		if c, ok := e.nodes[childID]; ok && c.Type == "text" {
+			b.WriteString(c.Text)
+		}
+	}
+}
+
+// Styles returns the scoped styles of the mounted components, keyed by the
+// identifier of the style element that contains them in the remote dom.
+func (e *Engine) Styles() map[string]string {
+	e.once.Do(e.init)
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	styles := make(map[string]string, len(e.styles))
+
+	for _, c := range e.compos {
+		if _, ok := styles[c.Scope]; ok || len(c.Scope) == 0 {
+			continue
+		}
+
+		styles[c.Scope] = scopeCSS(c.Compo.(app.Styler).Styles(), c.Scope)
+	}
+
+	return styles
+}
@@ -0,0 +1,106 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Exported struct {
+	Title string
+	Items []string
+}
+
+func (e *Exported) Styles() string {
+	return `h1 { color: red; }`
+}
+
+func (e *Exported) Render() string {
+	return `
+	<div class="page">
+		<h1 onclick="Refresh">{{.Title}}</h1>
+		<img src="logo.png" alt="a & b">
+		<input type="checkbox" checked>
+		<portal target="body"><p>modal</p></portal>
+		<ul>
+			{{range .Items}}
+			<li>{{.}}</li>
+			{{end}}
+		</ul>
+		<svg><path d="M 42.42 Z"></path></svg>
+		<dom.bar>
+	</div>
+	`
+}
+
+func (e *Exported) Refresh() {}
+
+func TestEngineHTML(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Exported{})
+	f.RegisterCompo(&Bar{})
+
+	e := &Engine{
+		Factory:        f,
+		AttrTransforms: []Transform{JsToGoHandler},
+	}
+	defer e.Close()
+
+	require.NoError(t, e.New(&Exported{
+		Title: "<Hello>",
+		Items: []string{"a", "b"},
+	}))
+
+	scope := styleScope("dom.exported")
+
+	assert.Equal(t, ``+
+		`<div class="page" `+scope+`>`+
+		`<h1 `+scope+`>&lt;Hello&gt;</h1>`+
+		`<img alt="a &amp; b" `+scope+` src="logo.png">`+
+		`<input checked `+scope+` type="checkbox">`+
+		`<ul `+scope+`><li `+scope+`>a</li><li `+scope+`>b</li></ul>`+
+		`<svg xmlns="http://www.w3.org/2000/svg" `+scope+`><path d="M 42.42 Z" `+scope+`></path></svg>`+
+		`<div>hello<h1>world</h1></div>`+
+		`</div>`,
+		e.HTML(),
+	)
+
+	assert.Equal(t, map[string]string{
+		scope: scopeCSS(`h1 { color: red; }`, scope),
+	}, e.Styles())
+}
+
+type RawText struct {
+	app.ZeroCompo
+}
+
+func (r *RawText) Render() string {
+	return `
+	<div>
+		<style>p > a { content: "&"; }</style>
+		<script>if (a < b && c) {}</script>
+		<textarea>a < b</textarea>
+	</div>
+	`
+}
+
+func TestEngineHTMLRawText(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&RawText{})
+
+	e := &Engine{Factory: f}
+	defer e.Close()
+
+	require.NoError(t, e.New(&RawText{}))
+
+	assert.Equal(t, ``+
+		`<div>`+
+		`<style>p > a { content: "&"; }</style>`+
+		`<script>if (a < b && c) {}</script>`+
+		`<textarea>a &lt; b</textarea>`+
+		`</div>`,
+		e.HTML(),
+	)
+}
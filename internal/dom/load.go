@@ -0,0 +1,109 @@
+package dom
+
+import (
+	"context"
+
+	"github.com/murlokswarm/app"
+)
+
+// asyncLoad describes the state of the asynchronous load of a component.
+type asyncLoad struct {
+	pending bool
+	err     error
+	cancel  func()
+}
+
+func (l *asyncLoad) loading() bool {
+	return l != nil && l.pending
+}
+
+func (l *asyncLoad) loadErr() error {
+	if l == nil {
+		return nil
+	}
+
+	return l.err
+}
+
+// Loading reports whether a mounted component is loading its content.
+func (e *Engine) Loading() bool {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for _, c := range e.compos {
+		if c.Load.loading() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Engine) startLoad(c compo) {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Load.cancel = cancel
+
+	go func() {
+		apply, err := load(ctx, c.Compo.(app.Loader))
+		if ctx.Err() != nil {
+			return
+		}
+
+		e.CallOnUIGoroutine(func() {
+			e.applyLoad(ctx, c, apply, err)
+		})
+	}()
+}
+
+func (e *Engine) applyLoad(ctx context.Context, c compo, apply func(), err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	// The component has been dismounted while loading.
+	if ctx.Err() != nil {
+		return
+	}
+
+	if err == nil && apply != nil {
+		err = applyLoaded(c.Compo, apply)
+	}
+
+	c.Load.pending = false
+	c.Load.err = err
+
+	if isCompoErr(err) {
+		e.ReportErr(err)
+	}
+
+	e.startProfile(c.Compo)
+	if err = e.syncAfter(e.renderBounded(c.Compo)); err != nil {
+		e.ReportErr(err)
+	}
+}
+
+func (e *Engine) cancelLoad(c compo) {
+	if c.Load != nil && c.Load.cancel != nil {
+		c.Load.cancel()
+	}
+}
+
+func load(ctx context.Context, l app.Loader) (apply func(), err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = compoPanic(l, r)
+		}
+	}()
+
+	return l.Load(ctx)
+}
+
+func applyLoaded(c app.Compo, apply func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = compoPanic(c, r)
+		}
+	}()
+
+	apply()
+	return nil
+}
@@ -0,0 +1,160 @@
+package dom
+
+import (
+	"context"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Lazy struct {
+	Fail    bool
+	Panic   bool
+	Content string
+
+	block    chan struct{}
+	canceled chan error
+}
+
+func (l *Lazy) Load(ctx context.Context) (func(), error) {
+	select {
+	case <-l.block:
+	case <-ctx.Done():
+		l.canceled <- ctx.Err()
+		return nil, ctx.Err()
+	}
+
+	if l.Panic {
+		panic("load")
+	}
+
+	if l.Fail {
+		return nil, errors.New("simulated err")
+	}
+
+	return func() {
+		l.Content = "loaded"
+	}, nil
+}
+
+func (l *Lazy) Render() string {
+	return `
+	<div>
+		{{if loading}}
+			<p>loading</p>
+		{{else if loadErr}}
+			<p>{{loadErr}}</p>
+		{{else}}
+			<p>{{.Content}}</p>
+		{{end}}
+	</div>
+	`
+}
+
+func TestEngineLoad(t *testing.T) {
+	tests := []struct {
+		scenario string
+		compo    *Lazy
+		text     string
+		reported bool
+	}{
+		{
+			scenario: "load succeeds",
+			compo:    &Lazy{},
+			text:     "loaded",
+		},
+		{
+			scenario: "load fails",
+			compo:    &Lazy{Fail: true},
+			text:     "simulated err",
+		},
+		{
+			scenario: "load panics",
+			compo:    &Lazy{Panic: true},
+			text:     "dom.lazy: panic: load",
+			reported: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			e, ui, reported := newLoadEngine()
+			defer e.Close()
+
+			c := test.compo
+			c.block = make(chan struct{})
+			c.canceled = make(chan error, 1)
+
+			err := e.New(c)
+			require.NoError(t, err)
+			assert.Equal(t, "loading", lazyText(e))
+			assert.True(t, e.Loading())
+
+			close(c.block)
+			(<-ui)()
+			assert.Equal(t, test.text, lazyText(e))
+			assert.False(t, e.Loading())
+			assert.Equal(t, test.reported, *reported != nil)
+		})
+	}
+}
+
+func TestEngineLoadCanceled(t *testing.T) {
+	e, ui, _ := newLoadEngine()
+	defer e.Close()
+
+	c := &Lazy{
+		block:    make(chan struct{}),
+		canceled: make(chan error, 1),
+	}
+
+	err := e.New(c)
+	require.NoError(t, err)
+
+	e.Close()
+	assert.Error(t, <-c.canceled)
+
+	select {
+	case <-ui:
+		assert.Fail(t, "canceled load applied")
+	default:
+	}
+
+	assert.Empty(t, c.Content)
+}
+
+func newLoadEngine() (*Engine, chan func(), *error) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Lazy{})
+
+	ui := make(chan func(), 1)
+	var reported error
+
+	e := &Engine{
+		Factory: f,
+		CallOnUIGoroutine: func(f func()) {
+			ui <- f
+		},
+		ReportErr: func(err error) {
+			reported = err
+		},
+	}
+
+	return e, ui, &reported
+}
+
+func lazyText(e *Engine) string {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	for _, n := range e.nodes {
+		if n.Type == "text" {
+			return n.Text
+		}
+	}
+
+	return ""
+}
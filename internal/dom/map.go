@@ -22,6 +22,10 @@ type Mapping struct {
 	JSONValue string
 
 	// A string that describes a field that may required override.
+	// "Files" indicates that the value is completed by the driver with the
+	// dropped files. "Submit" indicates that the mapping comes from a form
+	// submission: all the component fields are validated before calling the
+	// method.
 	Override string
 
 	pipeline []string
@@ -29,12 +33,41 @@ type Mapping struct {
 }
 
 // Map performs the mapping to the given component.
-func (m *Mapping) Map(c app.Compo) (f func(), err error) {
+// The returned func is not nil when the mapping targets a method. It calls the
+// method and reports a panic that occurs within it as an app.CompoError.
+//
+// Mapped fields of components that embed an app.Form are validated. On form
+// submissions, all the fields are validated and no func is returned when one
+// of them is not valid.
+func (m *Mapping) Map(c app.Compo) (f func() error, err error) {
 	if m.pipeline, err = pipeline(m.FieldOrMethod); err != nil {
 		return nil, err
 	}
 
-	return m.mapTo(reflect.ValueOf(c))
+	call, err := m.mapTo(reflect.ValueOf(c))
+	if err != nil {
+		return nil, err
+	}
+
+	if call == nil {
+		app.ValidateField(c, m.pipeline[0])
+		return nil, nil
+	}
+
+	if m.Override == "Submit" && !app.Validate(c) {
+		return nil, nil
+	}
+
+	return func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = compoPanic(c, r)
+			}
+		}()
+
+		call()
+		return nil
+	}, nil
 }
 
 func (m *Mapping) currentPipeline() []string {
@@ -3,6 +3,7 @@ package dom
 import (
 	"testing"
 
+	"github.com/murlokswarm/app"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -296,7 +297,7 @@ func TestMapping(t *testing.T) {
 
 			if test.isFunc {
 				require.NotNil(t, f)
-				f()
+				require.NoError(t, f())
 				require.Equal(t, 42, mappedInt)
 			}
 		})
@@ -362,3 +363,80 @@ func TestPipeline(t *testing.T) {
 		})
 	}
 }
+
+type MappingForm struct {
+	app.Form
+
+	Email     string `validate:"required,email"`
+	Submitted bool
+}
+
+func (f *MappingForm) Render() string {
+	return `<form onsubmit="Submit"></form>`
+}
+
+func (f *MappingForm) Submit() {
+	f.Submitted = true
+}
+
+func TestMappingValidation(t *testing.T) {
+	f := &MappingForm{}
+
+	m := Mapping{FieldOrMethod: "Email", JSONValue: `"max"`}
+	fn, err := m.Map(f)
+	require.NoError(t, err)
+	assert.Nil(t, fn)
+	assert.NotEmpty(t, f.FieldErr("Email"))
+
+	m = Mapping{FieldOrMethod: "Submit", JSONValue: "{}", Override: "Submit"}
+	fn, err = m.Map(f)
+	require.NoError(t, err)
+	assert.Nil(t, fn)
+
+	m = Mapping{FieldOrMethod: "Email", JSONValue: `"max@example.com"`}
+	_, err = m.Map(f)
+	require.NoError(t, err)
+	assert.Empty(t, f.FieldErr("Email"))
+	assert.True(t, f.Valid())
+
+	m = Mapping{FieldOrMethod: "Submit", JSONValue: "{}", Override: "Submit"}
+	fn, err = m.Map(f)
+	require.NoError(t, err)
+	require.NotNil(t, fn)
+	require.NoError(t, fn())
+	assert.True(t, f.Submitted)
+}
+
+type MountedForm struct {
+	app.Form
+
+	Email string `validate:"required,email"`
+}
+
+func (f *MountedForm) Render() string {
+	return `
+	<form onsubmit="Submit">
+		<input onchange="Email">
+		<p>{{.FieldErr "Email"}}</p>
+		<button {{if not .Valid}}disabled{{end}}>Sign up</button>
+	</form>`
+}
+
+func TestEngineValidateOnMount(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&MountedForm{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	c := &MountedForm{}
+	err := e.New(c)
+	require.NoError(t, err)
+	assert.False(t, c.Valid())
+	assert.Empty(t, c.FieldErr("Email"))
+
+	c = &MountedForm{Email: "max@example.com"}
+	err = e.New(c)
+	require.NoError(t, err)
+	assert.True(t, c.Valid())
+}
@@ -43,6 +43,8 @@ type change struct {
 	ChildID    string `json:",omitempty"`
 	NewChildID string `json:",omitempty"`
 	IsCompo    bool   `json:",omitempty"`
+	Enter      string `json:",omitempty"`
+	Leave      string `json:",omitempty"`
 }
 
 type changeAction int
@@ -57,6 +59,8 @@ const (
 	appendChild
 	removeChild
 	replaceChild
+	setStyle
+	delStyle
 )
 
 func clearChanges(c []change) []change {
@@ -69,6 +73,7 @@ func clearChanges(c []change) []change {
 
 var (
 	svg             = "http://www.w3.org/2000/svg"
+	portal          = "portal"
 	svgSpecialAttrs map[string]string
 	voidElems       = map[string]struct{}{
 		"area":   {},
@@ -87,6 +92,18 @@ var (
 		"track":  {},
 		"wbr":    {},
 	}
+
+	// rawTextElems are the elements whose text content is not escaped.
+	rawTextElems = map[string]struct{}{
+		"iframe":    {},
+		"noembed":   {},
+		"noframes":  {},
+		"noscript":  {},
+		"plaintext": {},
+		"script":    {},
+		"style":     {},
+		"xmp":       {},
+	}
 )
 
 func init() {
@@ -190,7 +207,7 @@ func svgAttr(k string) string {
 }
 
 func isHTMLNode(tagName string) bool {
-	return atom.Lookup([]byte(tagName)) != 0
+	return tagName == portal || atom.Lookup([]byte(tagName)) != 0
 }
 
 func isVoidElem(tagName string) bool {
@@ -198,6 +215,11 @@ func isVoidElem(tagName string) bool {
 	return ok
 }
 
+func isRawTextElem(tagName string) bool {
+	_, ok := rawTextElems[tagName]
+	return ok
+}
+
 func isCompoNode(tagName, namespace string) bool {
 	if len(namespace) != 0 {
 		return false
@@ -27,8 +27,28 @@ type Page struct {
 	// The name of the javascript function to pass data to Go.
 	GoRequest string
 
+	// The name of the javascript function to call exposed Go functions.
+	GoCall string
+
 	// The name of the root component.
 	RootCompoName string
+
+	// The markup of the root component rendered on the server side. It is
+	// replaced when the client renders the root component.
+	Body string
+
+	// The component styles rendered on the server side, keyed by style
+	// element identifier.
+	Styles map[string]string
+
+	// Reports whether the page is rendered without the client runtime.
+	Static bool
+
+	// The path of the web app manifest.
+	Manifest string
+
+	// The path of the service worker to register.
+	ServiceWorker string
 }
 
 func (p Page) String() string {
@@ -42,7 +62,13 @@ func (p Page) String() string {
 		Javascripts   []string
 		PageJS        string
 		GoRequest     string
+		GoCall        string
 		RootCompoName string
+		Body          string
+		Styles        map[string]string
+		Static        bool
+		Manifest      string
+		ServiceWorker string
 	}{
 		Title:         p.Title,
 		Metas:         p.Metas,
@@ -50,7 +76,13 @@ func (p Page) String() string {
 		Javascripts:   p.Javascripts,
 		PageJS:        jsTmpl,
 		GoRequest:     p.GoRequest,
+		GoCall:        p.GoCall,
 		RootCompoName: p.RootCompoName,
+		Body:          p.Body,
+		Styles:        p.Styles,
+		Static:        p.Static,
+		Manifest:      p.Manifest,
+		ServiceWorker: p.ServiceWorker,
 	})
 
 	return b.String()
@@ -1,14 +1,53 @@
 package dom
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestPageString(t *testing.T) {
 	p := Page{
 		GoRequest:     "alert",
+		GoCall:        "alert",
 		RootCompoName: "hello.hello",
 	}
 
 	t.Log(p)
 }
+
+func TestPageStringPrerendered(t *testing.T) {
+	p := Page{
+		GoRequest:     "alert",
+		GoCall:        "alert",
+		RootCompoName: "hello.hello",
+		Body:          `<h1>hello</h1>`,
+		Styles:        map[string]string{"data-goapp-42": "h1[data-goapp-42] {}"},
+	}
+
+	s := p.String()
+	assert.Contains(t, s, "<body><h1>hello</h1>")
+	assert.Contains(t, s, `<style id="data-goapp-42" type="text/css">h1[data-goapp-42] {}</style>`)
+	assert.Contains(t, s, "var loadedComp")
+
+	p.Static = true
+	s = p.String()
+	assert.Contains(t, s, "<body><h1>hello</h1>")
+	assert.False(t, strings.Contains(s, "var loadedComp"))
+}
+
+func TestPageStringManifest(t *testing.T) {
+	p := Page{}
+
+	s := p.String()
+	assert.False(t, strings.Contains(s, `rel="manifest"`))
+	assert.False(t, strings.Contains(s, "serviceWorker"))
+
+	p.Manifest = "/resources/manifest.webmanifest"
+	p.ServiceWorker = "/goapp-sw.js"
+
+	s = p.String()
+	assert.Contains(t, s, `<link rel="manifest" href="/resources/manifest.webmanifest">`)
+	assert.Contains(t, s, `navigator.serviceWorker.register('/goapp-sw.js')`)
+}
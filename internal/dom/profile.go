@@ -0,0 +1,72 @@
+package dom
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/murlokswarm/app"
+)
+
+// startProfile starts profiling the rendering of the given component. The
+// profile is reported when the rendering is synchronized.
+func (e *Engine) startProfile(c app.Compo) {
+	if e.Profile == nil && !app.Profiling() {
+		return
+	}
+
+	e.profile = &app.RenderProfile{
+		Compo: app.CompoName(c),
+		Start: time.Now(),
+	}
+}
+
+func (e *Engine) profileTemplate(c app.Compo, start time.Time) {
+	if e.profile == nil {
+		return
+	}
+
+	e.profile.Templates = append(e.profile.Templates, app.TemplateProfile{
+		Compo:    app.CompoName(c),
+		Start:    start,
+		Duration: time.Since(start),
+	})
+}
+
+func (e *Engine) profileSync(changes interface{}) {
+	for _, c := range e.creates {
+		if c.Action == newNode {
+			e.profile.Created++
+		}
+	}
+
+	for _, c := range e.deletes {
+		if c.Action == delNode {
+			e.profile.Deleted++
+		}
+	}
+
+	e.profile.Changed = len(e.changes)
+
+	if b, ok := changes.([]byte); ok {
+		e.profile.SyncSize = len(b)
+	} else if b, err := json.Marshal(changes); err == nil {
+		e.profile.SyncSize = len(b)
+	}
+}
+
+func (e *Engine) endProfile() {
+	p := e.profile
+	if p == nil {
+		return
+	}
+
+	e.profile = nil
+	p.Duration = time.Since(p.Start)
+
+	if e.Profile != nil {
+		e.Profile(*p)
+		return
+	}
+
+	app.ReportRenderProfile(*p)
+}
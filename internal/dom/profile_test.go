@@ -0,0 +1,81 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Profiled struct {
+	Value string
+}
+
+func (p *Profiled) Render() string {
+	return `
+	<div>
+		<p>{{.Value}}</p>
+		<dom.bar>
+	</div>
+	`
+}
+
+func TestEngineProfile(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Profiled{})
+	f.RegisterCompo(&Bar{})
+
+	var profiles []app.RenderProfile
+
+	e := Engine{
+		Factory: f,
+		Profile: func(p app.RenderProfile) {
+			profiles = append(profiles, p)
+		},
+	}
+	defer e.Close()
+
+	c := &Profiled{Value: "hello"}
+	err := e.New(c)
+	require.NoError(t, err)
+	require.Len(t, profiles, 1)
+
+	p := profiles[0]
+	assert.Equal(t, "dom.profiled", p.Compo)
+	assert.False(t, p.Start.IsZero())
+	assert.NotZero(t, p.Duration)
+	assert.Equal(t, len(e.nodes), p.Created)
+	assert.Zero(t, p.Deleted)
+	assert.NotZero(t, p.SyncSize)
+
+	require.Len(t, p.Templates, 2)
+	assert.Equal(t, "dom.profiled", p.Templates[0].Compo)
+	assert.Equal(t, "dom.bar", p.Templates[1].Compo)
+
+	c.Value = "world"
+	err = e.Render(c)
+	require.NoError(t, err)
+	require.Len(t, profiles, 2)
+
+	p = profiles[1]
+	assert.Zero(t, p.Created)
+	assert.Equal(t, 1, p.Changed)
+	assert.Equal(t, "dom.profiled", p.Templates[0].Compo)
+
+	err = e.Render(&Profiled{})
+	assert.Error(t, err)
+	assert.Len(t, profiles, 2)
+}
+
+func TestEngineNotProfiled(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Bar{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	err := e.New(&Bar{})
+	require.NoError(t, err)
+	assert.Nil(t, e.profile)
+}
@@ -0,0 +1,71 @@
+package dom
+
+import (
+	"reflect"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+)
+
+var refType = reflect.TypeOf(app.Ref{})
+
+func (e *Engine) updateRef(n node, prevRef string) error {
+	ref := n.Attrs["ref"]
+
+	if len(prevRef) != 0 && prevRef != ref {
+		e.unbindRef(n, prevRef)
+	}
+
+	if len(ref) == 0 {
+		return nil
+	}
+
+	c, ok := e.compoIDs[n.CompoID]
+	if !ok {
+		return nil
+	}
+
+	field, err := refField(c.Compo, ref)
+	if err != nil {
+		return err
+	}
+
+	field.Set(reflect.ValueOf(app.NewRef(c.Compo, n.ID)))
+	return nil
+}
+
+func (e *Engine) unbindRef(n node, ref string) {
+	c, ok := e.compoIDs[n.CompoID]
+	if !ok {
+		return
+	}
+
+	field, err := refField(c.Compo, ref)
+	if err != nil {
+		return
+	}
+
+	// The field may already be bound to a node that replaced n.
+	if field.Interface().(app.Ref).NodeID() == n.ID {
+		field.Set(reflect.Zero(refType))
+	}
+}
+
+func refField(c app.Compo, name string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+
+	if !isExported(name) {
+		return reflect.Value{}, errors.Errorf("ref %s is mapped to an unexported field", name)
+	}
+
+	field := v.FieldByName(name)
+	if !field.IsValid() {
+		return reflect.Value{}, errors.Errorf("ref %s is mapped to a nonexistent field", name)
+	}
+
+	if field.Type() != refType {
+		return reflect.Value{}, errors.Errorf("ref %s is mapped to a field that is not an app.Ref", name)
+	}
+
+	return field, nil
+}
@@ -0,0 +1,98 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type RefCompo struct {
+	Input     app.Ref
+	NoInput   bool
+	BadRef    bool
+	NotRef    string
+	unexposed app.Ref
+}
+
+func (r *RefCompo) Render() string {
+	return `
+	<div>
+		{{if not .NoInput}}
+			<input ref="Input">
+		{{end}}
+
+		{{if .BadRef}}
+			<p ref="NotRef"></p>
+		{{end}}
+	</div>
+	`
+}
+
+func TestEngineRef(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&RefCompo{})
+
+	e := Engine{Factory: f}
+	defer e.Close()
+
+	c := &RefCompo{}
+	err := e.New(c)
+	require.NoError(t, err)
+	require.True(t, c.Input.IsSet())
+
+	n, ok := e.nodes[c.Input.NodeID()]
+	require.True(t, ok)
+	assert.Equal(t, "input", n.Type)
+
+	c.NoInput = true
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.False(t, c.Input.IsSet())
+
+	c.BadRef = true
+	err = e.Render(c)
+	assert.Error(t, err)
+}
+
+func TestRefField(t *testing.T) {
+	tests := []struct {
+		scenario string
+		name     string
+		err      bool
+	}{
+		{
+			scenario: "ref field",
+			name:     "Input",
+		},
+		{
+			scenario: "nonexistent field",
+			name:     "Output",
+			err:      true,
+		},
+		{
+			scenario: "non ref field",
+			name:     "NotRef",
+			err:      true,
+		},
+		{
+			scenario: "unexported field",
+			name:     "unexposed",
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			_, err := refField(&RefCompo{}, test.name)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
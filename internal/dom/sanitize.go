@@ -0,0 +1,208 @@
+package dom
+
+import (
+	"bytes"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/murlokswarm/app"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Policy sanitizes markup with an app.SanitizePolicy.
+type Policy struct {
+	app.SanitizePolicy
+
+	once    sync.Once
+	tags    map[string]struct{}
+	attrs   map[string]struct{}
+	schemes map[string]struct{}
+}
+
+// NewPolicy returns a policy that sanitizes markup with the given
+// description.
+func NewPolicy(p app.SanitizePolicy) *Policy {
+	return &Policy{SanitizePolicy: p}
+}
+
+// StrictPolicy returns a policy that sanitizes markup with
+// app.StrictSanitizePolicy().
+func StrictPolicy() *Policy {
+	return NewPolicy(*app.StrictSanitizePolicy())
+}
+
+func (p *Policy) init() {
+	p.tags = toSet(p.Tags)
+	p.attrs = toSet(p.Attrs)
+	p.schemes = toSet(p.URLSchemes)
+}
+
+func toSet(s []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(s))
+	for _, v := range s {
+		set[strings.ToLower(v)] = struct{}{}
+	}
+	return set
+}
+
+// Sanitize returns the given markup without the tags, attributes and urls
+// that are not allowed.
+func (p *Policy) Sanitize(markup string) string {
+	return p.sanitize(markup, false)
+}
+
+// sanitize sanitizes the given markup. Component tags are kept when compos
+// is true.
+func (p *Policy) sanitize(markup string, compos bool) string {
+	p.once.Do(p.init)
+
+	var b bytes.Buffer
+	z := html.NewTokenizer(strings.NewReader(markup))
+
+	// The name of the tag whose content is being removed.
+	skipped := ""
+	skippedDepth := 0
+	foreignDepth := 0
+
+	for {
+		tt := z.Next()
+
+		switch tt {
+		case html.ErrorToken:
+			return b.String()
+
+		case html.TextToken:
+			if len(skipped) == 0 {
+				b.WriteString(html.EscapeString(string(z.Text())))
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			tag := string(name)
+
+			if len(skipped) != 0 {
+				if tag == skipped && tt == html.StartTagToken {
+					skippedDepth++
+				}
+				continue
+			}
+
+			compo := compos && foreignDepth == 0 && isCompoNode(tag, "")
+			if tt == html.StartTagToken && isForeignTag(tag) {
+				foreignDepth++
+			}
+
+			if !compo && !p.allowTag(tag) {
+				if _, ok := removedContentTags[tag]; ok && tt == html.StartTagToken && !isVoidElem(tag) {
+					skipped = tag
+					skippedDepth = 1
+				}
+				continue
+			}
+
+			b.WriteByte('<')
+			b.WriteString(tag)
+
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+
+				if compo || p.allowAttr(string(k), string(v)) {
+					b.WriteByte(' ')
+					b.Write(k)
+					b.WriteString(`="`)
+					b.WriteString(html.EscapeString(string(v)))
+					b.WriteByte('"')
+				}
+			}
+
+			if tt == html.SelfClosingTagToken {
+				b.WriteByte('/')
+			}
+			b.WriteByte('>')
+
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			tag := string(name)
+
+			if len(skipped) != 0 {
+				if tag == skipped {
+					if skippedDepth--; skippedDepth == 0 {
+						skipped = ""
+					}
+				}
+				continue
+			}
+
+			if isForeignTag(tag) && foreignDepth > 0 {
+				foreignDepth--
+			}
+
+			compo := compos && foreignDepth == 0 && isCompoNode(tag, "")
+			if compo || p.allowTag(tag) {
+				b.WriteString("</")
+				b.WriteString(tag)
+				b.WriteByte('>')
+			}
+		}
+	}
+}
+
+func (p *Policy) allowTag(tag string) bool {
+	_, ok := p.tags[tag]
+	return ok
+}
+
+func (p *Policy) allowAttr(k, v string) bool {
+	if strings.HasPrefix(k, "on") {
+		if strings.HasPrefix(v, "js:") {
+			return p.JSHandlers
+		}
+		return p.Handlers
+	}
+
+	if _, ok := p.attrs[k]; !ok {
+		return false
+	}
+
+	if !isURLAttr(k) {
+		return true
+	}
+
+	u, err := url.Parse(strings.TrimSpace(v))
+	if err != nil {
+		return false
+	}
+
+	if len(u.Scheme) == 0 {
+		return true
+	}
+
+	_, ok := p.schemes[strings.ToLower(u.Scheme)]
+	return ok
+}
+
+func isForeignTag(tag string) bool {
+	return tag == "svg" || tag == "math"
+}
+
+func isJSHandler(k, v string) bool {
+	return strings.HasPrefix(k, "on") && strings.HasPrefix(v, "js:")
+}
+
+// removedContentTags are the tags whose content is removed with them when
+// they are not allowed.
+var removedContentTags = map[string]struct{}{
+	atom.Script.String():   {},
+	atom.Style.String():    {},
+	atom.Iframe.String():   {},
+	atom.Object.String():   {},
+	atom.Embed.String():    {},
+	atom.Template.String(): {},
+	atom.Noscript.String(): {},
+	atom.Textarea.String(): {},
+	atom.Title.String():    {},
+	atom.Select.String():   {},
+}
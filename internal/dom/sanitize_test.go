@@ -0,0 +1,222 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicySanitize(t *testing.T) {
+	tests := []struct {
+		scenario string
+		policy   *Policy
+		markup   string
+		expected string
+	}{
+		{
+			scenario: "allowed markup",
+			policy:   StrictPolicy(),
+			markup:   `<p title="hi">Hello <b>world</b><br/></p>`,
+			expected: `<p title="hi">Hello <b>world</b><br/></p>`,
+		},
+		{
+			scenario: "disallowed tag content is kept",
+			policy:   StrictPolicy(),
+			markup:   `<p><blink>Hello</blink> <x.y>world</x.y></p>`,
+			expected: `<p>Hello world</p>`,
+		},
+		{
+			scenario: "script content is removed",
+			policy:   StrictPolicy(),
+			markup:   `<p>a<script>alert("<p>")</script>b<style>p{}</style>c</p>`,
+			expected: `<p>abc</p>`,
+		},
+		{
+			scenario: "nested removed content",
+			policy:   StrictPolicy(),
+			markup:   `<object><object></object>x</object>y`,
+			expected: `y`,
+		},
+		{
+			scenario: "disallowed attributes are removed",
+			policy:   StrictPolicy(),
+			markup:   `<p style="color: red" class="x" onclick="Delete" onmouseover="js:alert(1)">hi</p>`,
+			expected: `<p>hi</p>`,
+		},
+		{
+			scenario: "unsafe url schemes are removed",
+			policy:   StrictPolicy(),
+			markup:   `<a href="javascript:alert(1)">a</a><a href=" JavaScript:alert(1)">b</a><img src="data:image/png;base64,x">`,
+			expected: `<a>a</a><a>b</a><img>`,
+		},
+		{
+			scenario: "safe urls are kept",
+			policy:   StrictPolicy(),
+			markup:   `<a href="https://murlok.io">a</a><a href="main.hello">b</a>`,
+			expected: `<a href="https://murlok.io">a</a><a href="main.hello">b</a>`,
+		},
+		{
+			scenario: "text and attributes are escaped",
+			policy:   StrictPolicy(),
+			markup:   `<p title='"><script>'>1 &lt; 2 &amp; 3</p>`,
+			expected: `<p title="&#34;&gt;&lt;script&gt;">1 &lt; 2 &amp; 3</p>`,
+		},
+		{
+			scenario: "comments are removed",
+			policy:   StrictPolicy(),
+			markup:   `<p><!-- secret -->hi</p>`,
+			expected: `<p>hi</p>`,
+		},
+		{
+			scenario: "component tags are removed",
+			policy:   StrictPolicy(),
+			markup:   `<p><dom.bar title="x"></p>`,
+			expected: `<p></p>`,
+		},
+		{
+			scenario: "handlers",
+			policy: NewPolicy(app.SanitizePolicy{
+				Tags:     []string{"button"},
+				Handlers: true,
+			}),
+			markup:   `<button onclick="Click" onmouseover="js:alert(1)">ok</button>`,
+			expected: `<button onclick="Click">ok</button>`,
+		},
+		{
+			scenario: "js handlers",
+			policy: NewPolicy(app.SanitizePolicy{
+				Tags:       []string{"button"},
+				JSHandlers: true,
+			}),
+			markup:   `<button onclick="Click" onmouseover="js:alert(1)">ok</button>`,
+			expected: `<button onmouseover="js:alert(1)">ok</button>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			assert.Equal(t, test.expected, test.policy.Sanitize(test.markup))
+		})
+	}
+}
+
+func TestPolicySanitizeCompos(t *testing.T) {
+	p := NewPolicy(app.SanitizePolicy{Tags: []string{"p"}})
+
+	s := p.sanitize(`<p><dom.bar title="x"><svg><foo></foo></svg></p>`, true)
+	assert.Equal(t, `<p><dom.bar title="x"></p>`, s)
+}
+
+type Comment struct {
+	Content string
+}
+
+func (c *Comment) Render() string {
+	return `
+	<div onclick="Click">
+		<h1 onmouseover="js:alert('hi')">comment</h1>
+		<iframe src="https://murlok.io"></iframe>
+		<svg><circle r="1"></circle></svg>
+		<p>{{raw .Content}}</p>
+	</div>
+	`
+}
+
+func (c *Comment) Click() {}
+
+func TestEngineSanitize(t *testing.T) {
+	tests := []struct {
+		scenario string
+		config   app.SanitizeConfig
+		types    []string
+		attrs    map[string]bool
+	}{
+		{
+			scenario: "raw markup is not sanitized by default",
+			types:    []string{"div", "h1", "iframe", "svg", "circle", "p", "b", "script"},
+			attrs:    map[string]bool{"onclick": true, "onmouseover": true},
+		},
+		{
+			scenario: "strict raw policy",
+			config: app.SanitizeConfig{
+				Raw: app.StrictSanitizePolicy(),
+			},
+			types: []string{"div", "h1", "iframe", "svg", "circle", "p", "b"},
+			attrs: map[string]bool{"onclick": true, "onmouseover": true},
+		},
+		{
+			scenario: "custom raw policy",
+			config: app.SanitizeConfig{
+				Raw: &app.SanitizePolicy{Tags: []string{"b", "script"}},
+			},
+			types: []string{"div", "h1", "iframe", "svg", "circle", "p", "b", "script"},
+			attrs: map[string]bool{"onclick": true, "onmouseover": true},
+		},
+		{
+			scenario: "policy for all markup",
+			config: app.SanitizeConfig{
+				Raw: app.StrictSanitizePolicy(),
+				Markup: &app.SanitizePolicy{
+					Tags:     []string{"div", "h1", "p", "b", "svg", "circle"},
+					Handlers: true,
+				},
+			},
+			types: []string{"div", "h1", "svg", "circle", "p", "b"},
+			attrs: map[string]bool{"onclick": true},
+		},
+		{
+			scenario: "disabled js handlers",
+			config: app.SanitizeConfig{
+				Raw:               app.StrictSanitizePolicy(),
+				DisableJSHandlers: true,
+			},
+			types: []string{"div", "h1", "iframe", "svg", "circle", "p", "b"},
+			attrs: map[string]bool{"onclick": true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			f := app.NewFactory()
+			f.RegisterCompo(&Comment{})
+
+			e := Engine{
+				Factory:  f,
+				Sanitize: test.config,
+			}
+			defer e.Close()
+
+			err := e.New(&Comment{
+				Content: `<b onclick="Delete">hello</b><script>alert("hi")</script>`,
+			})
+			require.NoError(t, err)
+
+			types := make(map[string]bool)
+			attrs := make(map[string]bool)
+
+			for _, n := range e.nodes {
+				if n.Type == "text" || n.Type == "dom.comment" {
+					continue
+				}
+
+				types[n.Type] = true
+
+				for k := range n.Attrs {
+					if len(k) > 2 && k[:2] == "on" {
+						attrs[k] = true
+					}
+				}
+			}
+
+			expected := make(map[string]bool, len(test.types))
+			for _, typ := range test.types {
+				expected[typ] = true
+			}
+
+			assert.Equal(t, expected, types)
+			assert.Equal(t, test.attrs, attrs)
+		})
+	}
+}
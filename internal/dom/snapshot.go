@@ -0,0 +1,232 @@
+package dom
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/murlokswarm/app"
+	"github.com/pkg/errors"
+)
+
+// snapshot describes the state of the components and nodes of an engine.
+type snapshot struct {
+	Root *nodeSnapshot
+}
+
+// nodeSnapshot describes a node and its children. Fields contains the
+// exported fields of a component node.
+type nodeSnapshot struct {
+	Type      string
+	Namespace string                     `json:",omitempty"`
+	Text      string                     `json:",omitempty"`
+	Attrs     map[string]string          `json:",omitempty"`
+	IsCompo   bool                       `json:",omitempty"`
+	Fields    map[string]json.RawMessage `json:",omitempty"`
+	Children  []*nodeSnapshot            `json:",omitempty"`
+}
+
+// Snapshot returns the mounted components and their nodes encoded in JSON.
+// Components are described by their type and their exported fields.
+// Fields tagged with `snapshot:"-"` and fields that contain funcs or channels
+// are ignored.
+func (e *Engine) Snapshot() ([]byte, error) {
+	e.once.Do(e.init)
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+
+	root, err := e.snapshotNode(e.rootID)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(snapshot{Root: root})
+}
+
+func (e *Engine) snapshotNode(id string) (*nodeSnapshot, error) {
+	n, ok := e.nodes[id]
+	if !ok {
+		return nil, nil
+	}
+
+	s := &nodeSnapshot{
+		Type:      n.Type,
+		Namespace: n.Namespace,
+		Text:      n.Text,
+		Attrs:     n.Attrs,
+		IsCompo:   n.IsCompo,
+	}
+
+	if n.IsCompo {
+		c := e.compoIDs[n.ID]
+
+		fields, err := snapshotFields(c.Compo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "snapshotting %s failed", n.Type)
+		}
+		s.Fields = fields
+	}
+
+	for _, childID := range n.ChildIDs {
+		child, err := e.snapshotNode(childID)
+		if err != nil {
+			return nil, err
+		}
+
+		if child != nil {
+			s.Children = append(s.Children, child)
+		}
+	}
+
+	return s, nil
+}
+
+func snapshotFields(c app.Compo) (map[string]json.RawMessage, error) {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	fields := make(map[string]json.RawMessage, t.NumField())
+
+	for i, numfields := 0, t.NumField(); i < numfields; i++ {
+		ft := t.Field(i)
+
+		if !isSnapshotField(ft) {
+			continue
+		}
+
+		b, err := json.Marshal(v.Field(i).Interface())
+		if err != nil {
+			return nil, errors.Wrapf(err, "encoding field %s failed", ft.Name)
+		}
+
+		fields[ft.Name] = b
+	}
+
+	return fields, nil
+}
+
+func isSnapshotField(f reflect.StructField) bool {
+	if len(f.PkgPath) != 0 || f.Anonymous || f.Tag.Get("snapshot") == "-" {
+		return false
+	}
+
+	switch f.Type.Kind() {
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return false
+	}
+
+	return true
+}
+
+// Restore rebuilds the components described by the given snapshot and
+// returns the root component.
+//
+// Components are created like with New: their exported fields are restored
+// before they are mounted, so OnMount is called once for each component with
+// the restored state. Fields that are set by a parent component are then
+// overridden by its attributes, like when it is rendered.
+func (e *Engine) Restore(data []byte) (app.Compo, error) {
+	e.once.Do(e.init)
+
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, errors.Wrap(err, "decoding snapshot failed")
+	}
+
+	if s.Root == nil || !s.Root.IsCompo {
+		return nil, errors.New("snapshot does not have a root component")
+	}
+
+	c, err := e.Factory.NewCompo(s.Root.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.restoring = map[string][]*nodeSnapshot{"": {s.Root}}
+	defer func() {
+		e.restoring = nil
+	}()
+
+	return c, e.newRoot(c)
+}
+
+// restoreCompo restores the state of the component that is created for the
+// given node. It is called before the component is mounted.
+func (e *Engine) restoreCompo(c app.Compo, n node) error {
+	if e.restoring == nil {
+		return nil
+	}
+
+	s := e.popRestored(n.CompoID, n.Type)
+	if s == nil {
+		return nil
+	}
+
+	if err := restoreFields(c, s.Fields); err != nil {
+		return errors.Wrapf(err, "restoring %s failed", n.Type)
+	}
+
+	var children []*nodeSnapshot
+	for _, child := range s.Children {
+		children = appendCompoSnapshots(children, child)
+	}
+
+	e.restoring[n.ID] = children
+	return nil
+}
+
+// popRestored returns the next snapshot of a component with the given type
+// that was rendered by the component with the given id. Components are
+// restored in rendering order. Snapshots that precede the returned one are
+// discarded since their components are no longer rendered.
+func (e *Engine) popRestored(compoID, typ string) *nodeSnapshot {
+	snapshots := e.restoring[compoID]
+
+	for i, s := range snapshots {
+		if s.Type == typ {
+			e.restoring[compoID] = snapshots[i+1:]
+			return s
+		}
+	}
+
+	return nil
+}
+
+// appendCompoSnapshots appends the component snapshots of the given node tree
+// that are not within another component.
+func appendCompoSnapshots(snapshots []*nodeSnapshot, s *nodeSnapshot) []*nodeSnapshot {
+	if s.IsCompo {
+		return append(snapshots, s)
+	}
+
+	for _, child := range s.Children {
+		snapshots = appendCompoSnapshots(snapshots, child)
+	}
+
+	return snapshots
+}
+
+func restoreFields(c app.Compo, fields map[string]json.RawMessage) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	for i, numfields := 0, t.NumField(); i < numfields; i++ {
+		ft := t.Field(i)
+
+		if !isSnapshotField(ft) {
+			continue
+		}
+
+		value, ok := fields[ft.Name]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(value, v.Field(i).Addr().Interface()); err != nil {
+			return errors.Wrapf(err, "decoding field %s failed", ft.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,222 @@
+package dom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Snapshotted struct {
+	Title    string
+	Items    []string
+	Token    string `snapshot:"-"`
+	OnChange func()
+
+	mounts        int
+	mountedTitle  string
+	internalState string
+}
+
+func (s *Snapshotted) OnMount() {
+	s.mounts++
+	s.mountedTitle = s.Title
+}
+
+func (s *Snapshotted) Render() string {
+	return `
+	<div>
+		<h1>{{.Title}}</h1>
+		{{range .Items}}
+		<dom.snapshottedchild label="{{.}}">
+		{{end}}
+	</div>
+	`
+}
+
+type SnapshottedChild struct {
+	Label  string
+	Clicks int
+
+	mountedClicks int
+}
+
+func (c *SnapshottedChild) OnMount() {
+	c.mountedClicks = c.Clicks
+}
+
+func (c *SnapshottedChild) Render() string {
+	return `<p>{{.Label}}: {{.Clicks}}</p>`
+}
+
+func snapshotFactory() *app.Factory {
+	f := app.NewFactory()
+	f.RegisterCompo(&Snapshotted{})
+	f.RegisterCompo(&SnapshottedChild{})
+	return f
+}
+
+func TestEngineSnapshot(t *testing.T) {
+	e := &Engine{Factory: snapshotFactory()}
+	defer e.Close()
+
+	c := &Snapshotted{
+		Title:         "hello",
+		Items:         []string{"a", "b"},
+		Token:         "secret",
+		OnChange:      func() {},
+		internalState: "internal",
+	}
+	require.NoError(t, e.New(c))
+
+	for _, ic := range e.compos {
+		if child, ok := ic.Compo.(*SnapshottedChild); ok && child.Label == "b" {
+			child.Clicks = 42
+			require.NoError(t, e.Render(child))
+		}
+	}
+
+	data, err := e.Snapshot()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+	assert.NotContains(t, string(data), "internal")
+
+	restored := &Engine{Factory: snapshotFactory()}
+	defer restored.Close()
+
+	rc, err := restored.Restore(data)
+	require.NoError(t, err)
+
+	root := rc.(*Snapshotted)
+	assert.Equal(t, "hello", root.Title)
+	assert.Equal(t, []string{"a", "b"}, root.Items)
+	assert.Empty(t, root.Token)
+	assert.Nil(t, root.OnChange)
+	assert.Empty(t, root.internalState)
+	assert.Equal(t, 1, root.mounts)
+	assert.Equal(t, "hello", root.mountedTitle)
+
+	var children []*SnapshottedChild
+	for _, ic := range restored.compos {
+		if child, ok := ic.Compo.(*SnapshottedChild); ok {
+			children = append(children, child)
+		}
+	}
+	require.Len(t, children, 2)
+
+	for _, child := range children {
+		if child.Label == "b" {
+			assert.Equal(t, 42, child.Clicks)
+			assert.Equal(t, 42, child.mountedClicks)
+			continue
+		}
+		assert.Equal(t, "a", child.Label)
+		assert.Zero(t, child.Clicks)
+	}
+
+	restoredData, err := restored.Snapshot()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(restoredData))
+}
+
+func TestEngineSnapshotStructure(t *testing.T) {
+	e := &Engine{Factory: snapshotFactory()}
+	defer e.Close()
+
+	require.NoError(t, e.New(&Snapshotted{
+		Title: "hello",
+		Items: []string{"a"},
+	}))
+
+	data, err := e.Snapshot()
+	require.NoError(t, err)
+
+	var s snapshot
+	require.NoError(t, json.Unmarshal(data, &s))
+
+	root := s.Root
+	assert.Equal(t, "dom.snapshotted", root.Type)
+	assert.True(t, root.IsCompo)
+	assert.JSONEq(t, `"hello"`, string(root.Fields["Title"]))
+	assert.NotContains(t, root.Fields, "Token")
+	assert.NotContains(t, root.Fields, "OnChange")
+
+	div := root.Children[0]
+	assert.Equal(t, "div", div.Type)
+	require.Len(t, div.Children, 2)
+
+	h1 := div.Children[0]
+	assert.Equal(t, "h1", h1.Type)
+	assert.Equal(t, "hello", h1.Children[0].Text)
+
+	child := div.Children[1]
+	assert.Equal(t, "dom.snapshottedchild", child.Type)
+	assert.Equal(t, "a", child.Attrs["label"])
+	assert.JSONEq(t, `"a"`, string(child.Fields["Label"]))
+}
+
+func TestEngineRestoreError(t *testing.T) {
+	tests := []struct {
+		scenario string
+		data     string
+	}{
+		{
+			scenario: "invalid json",
+			data:     `{`,
+		},
+		{
+			scenario: "no root",
+			data:     `{}`,
+		},
+		{
+			scenario: "root is not a component",
+			data:     `{"Root":{"Type":"div"}}`,
+		},
+		{
+			scenario: "root is not registered",
+			data:     `{"Root":{"Type":"dom.unknown","IsCompo":true}}`,
+		},
+		{
+			scenario: "field with a wrong type",
+			data:     `{"Root":{"Type":"dom.snapshotted","IsCompo":true,"Fields":{"Title":42}}}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			e := &Engine{Factory: snapshotFactory()}
+			defer e.Close()
+
+			_, err := e.Restore([]byte(test.data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestEngineRestoreChangedTemplate(t *testing.T) {
+	e := &Engine{Factory: snapshotFactory()}
+	defer e.Close()
+
+	// The second child is no longer rendered and an unknown field is
+	// ignored.
+	c, err := e.Restore([]byte(`{"Root": {
+		"Type": "dom.snapshotted",
+		"IsCompo": true,
+		"Fields": {"Title": "hi", "Items": ["a"], "Removed": true},
+		"Children": [{"Type": "div", "Children": [
+			{"Type": "dom.snapshottedchild", "IsCompo": true, "Fields": {"Clicks": 1}},
+			{"Type": "dom.snapshottedchild", "IsCompo": true, "Fields": {"Clicks": 2}}
+		]}]
+	}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "hi", c.(*Snapshotted).Title)
+
+	for _, ic := range e.compos {
+		if child, ok := ic.Compo.(*SnapshottedChild); ok {
+			assert.Equal(t, 1, child.Clicks)
+			assert.Equal(t, "a", child.Label)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package dom
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/murlokswarm/app"
+)
+
+// styleScope returns the attribute that scopes the styles of the named
+// component type.
+func styleScope(compoName string) string {
+	h := fnv.New32a()
+	h.Write([]byte(compoName))
+	return fmt.Sprintf("data-goapp-%x", h.Sum32())
+}
+
+// scopeCSS restricts the rules of the given css to the nodes that have the
+// given attribute.
+func scopeCSS(css, attr string) string {
+	var b strings.Builder
+
+	for css = strings.TrimSpace(css); len(css) != 0; css = strings.TrimSpace(css) {
+		open := strings.IndexByte(css, '{')
+		if open < 0 {
+			b.WriteString(css)
+			break
+		}
+
+		prelude := strings.TrimSpace(css[:open])
+		body, rest := cssBlock(css[open+1:])
+		css = rest
+
+		switch {
+		case strings.HasPrefix(prelude, "@media"),
+			strings.HasPrefix(prelude, "@supports"),
+			strings.HasPrefix(prelude, "@document"):
+			fmt.Fprintf(&b, "%s {\n%s}\n", prelude, scopeCSS(body, attr))
+
+		case strings.HasPrefix(prelude, "@"):
+			fmt.Fprintf(&b, "%s {%s}\n", prelude, body)
+
+		default:
+			fmt.Fprintf(&b, "%s {%s}\n", scopeSelectors(prelude, attr), body)
+		}
+	}
+
+	return b.String()
+}
+
+// cssBlock returns the content of the block that starts at the beginning of s
+// and the remaining css after the block closing brace.
+func cssBlock(s string) (block, rest string) {
+	depth := 1
+
+	for i, c := range s {
+		switch c {
+		case '{':
+			depth++
+
+		case '}':
+			depth--
+			if depth == 0 {
+				return s[:i], s[i+1:]
+			}
+		}
+	}
+
+	return s, ""
+}
+
+func scopeSelectors(selectors, attr string) string {
+	s := strings.Split(selectors, ",")
+
+	for i, sel := range s {
+		s[i] = scopeSelector(strings.TrimSpace(sel), attr)
+	}
+
+	return strings.Join(s, ", ")
+}
+
+func scopeSelector(sel, attr string) string {
+	if len(sel) == 0 {
+		return sel
+	}
+
+	// The attribute is added to the last compound selector, before its
+	// pseudo classes and elements.
+	start := 0
+	end := -1
+	depth := 0
+
+	for i, c := range sel {
+		switch c {
+		case '[', '(':
+			depth++
+
+		case ']', ')':
+			depth--
+
+		case ' ', '>', '+', '~':
+			if depth == 0 {
+				start = i + 1
+				end = -1
+			}
+
+		case ':':
+			if depth == 0 && end < 0 {
+				end = i
+			}
+		}
+	}
+
+	if end < start {
+		end = len(sel)
+	}
+
+	return sel[:end] + "[" + attr + "]" + sel[end:]
+}
+
+func (e *Engine) mountStyles(c compo) {
+	if len(c.Scope) == 0 {
+		return
+	}
+
+	e.styles[c.Scope]++
+	if e.styles[c.Scope] != 1 {
+		return
+	}
+
+	e.changes = append(e.changes, change{
+		Action: setStyle,
+		Key:    c.Scope,
+		Value:  scopeCSS(c.Compo.(app.Styler).Styles(), c.Scope),
+	})
+}
+
+func (e *Engine) dismountStyles(c compo) {
+	if len(c.Scope) == 0 {
+		return
+	}
+
+	e.styles[c.Scope]--
+	if e.styles[c.Scope] > 0 {
+		return
+	}
+
+	delete(e.styles, c.Scope)
+	e.deletes = append(e.deletes, change{
+		Action: delStyle,
+		Key:    c.Scope,
+	})
+}
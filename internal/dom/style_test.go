@@ -0,0 +1,148 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Styled app.ZeroCompo
+
+func (s *Styled) Styles() string {
+	return `p { color: red; }`
+}
+
+func (s *Styled) Render() string {
+	return `<p>styled</p>`
+}
+
+type StyledParent struct {
+	Count int
+}
+
+func (s *StyledParent) Render() string {
+	return `
+	<div>
+		{{range $i, $v := .Items}}
+			<dom.styled>
+		{{end}}
+	</div>
+	`
+}
+
+func (s *StyledParent) Items() []int {
+	return make([]int, s.Count)
+}
+
+func TestScopeCSS(t *testing.T) {
+	tests := []struct {
+		scenario string
+		css      string
+		expected string
+	}{
+		{
+			scenario: "element",
+			css:      `p { color: red; }`,
+			expected: "p[s] { color: red; }\n",
+		},
+		{
+			scenario: "multiple selectors",
+			css:      `h1, .title { color: red; }`,
+			expected: "h1[s], .title[s] { color: red; }\n",
+		},
+		{
+			scenario: "descendant",
+			css:      `div > p span { margin: 0; }`,
+			expected: "div > p span[s] { margin: 0; }\n",
+		},
+		{
+			scenario: "pseudo class",
+			css:      `a:hover { color: blue; }`,
+			expected: "a[s]:hover { color: blue; }\n",
+		},
+		{
+			scenario: "pseudo element",
+			css:      `p::before { content: "x"; }`,
+			expected: "p[s]::before { content: \"x\"; }\n",
+		},
+		{
+			scenario: "attribute with space",
+			css:      `a[title="a b"] { color: blue; }`,
+			expected: "a[title=\"a b\"][s] { color: blue; }\n",
+		},
+		{
+			scenario: "media query",
+			css:      `@media (max-width: 600px) { p { color: red; } }`,
+			expected: "@media (max-width: 600px) {\np[s] { color: red; }\n}\n",
+		},
+		{
+			scenario: "keyframes",
+			css:      `@keyframes fade { from { opacity: 0; } to { opacity: 1; } }`,
+			expected: "@keyframes fade { from { opacity: 0; } to { opacity: 1; } }\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			assert.Equal(t, test.expected, scopeCSS(test.css, "s"))
+		})
+	}
+}
+
+func TestEngineStyles(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Styled{})
+	f.RegisterCompo(&StyledParent{})
+
+	var changes []change
+
+	e := Engine{
+		Factory: f,
+		Sync: func(v interface{}) error {
+			changes = append(changes, v.([]change)...)
+			return nil
+		},
+	}
+	defer e.Close()
+
+	countActions := func(a changeAction) int {
+		count := 0
+		for _, c := range changes {
+			if c.Action == a {
+				count++
+			}
+		}
+		return count
+	}
+
+	scope := styleScope("dom.styled")
+	c := &StyledParent{Count: 2}
+
+	err := e.New(c)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countActions(setStyle))
+	assert.Equal(t, 2, e.styles[scope])
+
+	for _, n := range e.nodes {
+		if n.Type == "p" {
+			assert.Contains(t, n.Attrs, scope)
+		}
+	}
+
+	c.Count = 1
+	changes = nil
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.Zero(t, countActions(setStyle))
+	assert.Zero(t, countActions(delStyle))
+	assert.Equal(t, 1, e.styles[scope])
+
+	c.Count = 0
+	changes = nil
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.Equal(t, 1, countActions(delStyle))
+	assert.NotContains(t, e.styles, scope)
+}
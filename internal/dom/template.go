@@ -7,7 +7,8 @@ const htmlTmpl = `<!DOCTYPE html>
     <meta charset="UTF-8">
     {{range .Metas}}<meta{{if .Name}} name="{{.Name}}"{{end}}{{if .HTTPEquiv}} http-equiv="{{.HTTPEquiv}}"{{end}}{{if .Content}} content="{{.Content}}"{{end}}>
     {{end}} 
-    <title>{{.Title}}</title>
+    <title>{{.Title}}</title>{{if .Manifest}}
+    <link rel="manifest" href="{{.Manifest}}">{{end}}
     <style media="all" type="text/css">
         html {
             height: 100%;
@@ -26,10 +27,12 @@ const htmlTmpl = `<!DOCTYPE html>
     </style>
     {{range .CSS}}
     <link type="text/css" rel="stylesheet" href="{{.}}">{{end}}
+    {{range $id, $css := .Styles}}
+    <style id="{{$id}}" type="text/css">{{$css}}</style>{{end}}
 </head>
-<body>
-    <div></div>
-
+<body>{{if .Body}}{{.Body}}{{else}}
+    <div></div>{{end}}
+    {{if not .Static}}
     <script>
 {{if .RootCompoName}}var loadedComp = '{{.RootCompoName}}';{{end}}
 
@@ -37,17 +40,31 @@ var golangRequest = function (payload) {
     {{.GoRequest}}(payload)
 }
 
+var golangCall = function (payload) {
+    {{.GoCall}}(payload)
+}
+
 {{.PageJS}}
     </script>
+    {{end}}
     
     {{range .Javascripts}}
     <script src="{{.}}"></script>{{end}}
+    {{if .ServiceWorker}}
+    <script>
+if ('serviceWorker' in navigator) {
+    navigator.serviceWorker.register('{{.ServiceWorker}}');
+}
+    </script>
+    {{end}}
 </body>
 </html>
 `
 const jsTmpl = `
 const goapp = {
     nodes: {},
+    calls: {},
+    callCount: 0,
 
     actions: Object.freeze({
         "setRoot": 0,
@@ -59,10 +76,35 @@ const goapp = {
         "appendChild": 6,
         "removeChild": 7,
         "replaceChild": 8,
-    })
+        "setStyle": 9,
+        "delStyle": 10,
+    }),
+
+    // The interned strings of the compact and binary encodings.
+    strings: [],
+
+    // The encoded change fields, in encoding order.
+    fields: Object.freeze([
+        "CompoID",
+        "Type",
+        "Namespace",
+        "Key",
+        "Value",
+        "ChildID",
+        "NewChildID",
+        "IsCompo",
+        "Enter",
+        "Leave",
+    ])
 };
 
 function render(changes = []) {
+    if (changes instanceof Uint8Array) {
+        changes = decodeBinary(changes);
+    } else if (changes.length && typeof changes[0] === 'number') {
+        changes = decodeCompact(changes);
+    }
+
     changes.forEach(c => {
         switch (c.Action) {
             case goapp.actions.setRoot:
@@ -101,12 +143,107 @@ function render(changes = []) {
                 replaceChild(c);
                 break;
 
+            case goapp.actions.setStyle:
+                setStyle(c);
+                break;
+
+            case goapp.actions.delStyle:
+                delStyle(c);
+                break;
+
             default:
                 console.log(c.Type + ' change is not supported');
         }
     });
 }
 
+function decodeCompact(data = []) {
+    const [reset, strings, ...encoded] = data;
+    internStrings(reset, strings);
+
+    return encoded.map(e => {
+        let i = 3;
+
+        return decodeChange(e[0], e[1], e[2], field => {
+            return field === 'Value' ? e[i++] : goapp.strings[e[i++]];
+        });
+    });
+}
+
+function decodeBinary(data = new Uint8Array()) {
+    const decoder = new TextDecoder();
+    let offset = 0;
+
+    const readUint = () => {
+        let n = 0;
+        let shift = 0;
+        let b = 0;
+
+        do {
+            b = data[offset++];
+            n += (b & 0x7f) * Math.pow(2, shift);
+            shift += 7;
+        } while (b & 0x80);
+
+        return n;
+    };
+
+    const readString = () => {
+        const len = readUint();
+        const s = decoder.decode(data.subarray(offset, offset + len));
+        offset += len;
+        return s;
+    };
+
+    const reset = data[offset++];
+    const strings = [];
+
+    for (let count = readUint(); count > 0; count--) {
+        strings.push(readString());
+    }
+
+    internStrings(reset, strings);
+
+    const changes = [];
+
+    for (let count = readUint(); count > 0; count--) {
+        const action = readUint();
+        const nodeID = readUint();
+        const mask = readUint();
+
+        changes.push(decodeChange(action, nodeID, mask, field => {
+            return field === 'Value' ? readString() : goapp.strings[readUint()];
+        }));
+    }
+
+    return changes;
+}
+
+function internStrings(reset, strings = []) {
+    if (reset) {
+        goapp.strings = [];
+    }
+
+    strings.forEach(s => goapp.strings.push(s));
+}
+
+function decodeChange(action, nodeID, mask, read) {
+    const c = {
+        Action: action,
+        NodeID: goapp.strings[nodeID]
+    };
+
+    goapp.fields.forEach((field, i) => {
+        if (!(mask & (1 << i))) {
+            return;
+        }
+
+        c[field] = field === 'IsCompo' ? true : read(field);
+    });
+
+    return c;
+}
+
 function setRoot(change = {}) {
     const { NodeID } = change;
 
@@ -139,6 +276,8 @@ function newNode(change = {}) {
 
     if (Type === 'text') {
         n = document.createTextNode("");
+    } else if (Type === 'portal') {
+        n = newPortal();
     } else if (change.Namespace) {
         n = document.createElementNS(Namespace, Type);
     } else {
@@ -152,9 +291,39 @@ function newNode(change = {}) {
 
 function delNode(change = {}) {
     const { NodeID } = change;
+
+    const n = goapp.nodes[NodeID];
+    if (n && n.Portal && n.Portal.parentNode) {
+        n.Portal.parentNode.removeChild(n.Portal);
+    }
+
     delete goapp.nodes[NodeID];
 }
 
+function newPortal() {
+    // A portal is represented by a placeholder at its location in the
+    // document. Its children are rendered into a container that is appended
+    // to the node that matches the portal target.
+    const n = document.createComment('portal');
+    n.Portal = document.createElement('div');
+    document.body.appendChild(n.Portal);
+    return n;
+}
+
+function setPortalTarget(n, target) {
+    var t = null;
+
+    if (target) {
+        t = document.querySelector(target);
+    }
+
+    if (!t) {
+        t = document.body;
+    }
+
+    t.appendChild(n.Portal);
+}
+
 function setAttr(change = {}) {
     const { NodeID, Key, Value = '' } = change;
 
@@ -163,6 +332,21 @@ function setAttr(change = {}) {
         return;
     }
 
+    if (n.Portal) {
+        if (Key === 'target') {
+            setPortalTarget(n, Value);
+            return;
+        }
+
+        n.Portal.setAttribute(Key, Value);
+        return;
+    }
+
+    if (isEventAttr(Key)) {
+        setEventHandler(n, Key.substr(2), Value);
+        return;
+    }
+
     n.setAttribute(Key, Value);
 }
 
@@ -174,9 +358,69 @@ function delAttr(change = {}) {
         return;
     }
 
+    if (n.Portal) {
+        if (Key === 'target') {
+            setPortalTarget(n, null);
+            return;
+        }
+
+        n.Portal.removeAttribute(Key);
+        return;
+    }
+
+    if (isEventAttr(Key)) {
+        setEventHandler(n, Key.substr(2), null);
+        return;
+    }
+
     n.removeAttribute(Key);
 }
 
+function isEventAttr(key) {
+    return key.startsWith('on');
+}
+
+// Event handlers are bound with addEventListener rather than set as inline
+// attributes in order to work with a Content-Security-Policy that forbids
+// inline scripts.
+function setEventHandler(n, type, value) {
+    n.Handlers = n.Handlers || {};
+
+    if (n.Handlers[type]) {
+        n.removeEventListener(type, n.Handlers[type]);
+        delete n.Handlers[type];
+    }
+
+    if (value === null) {
+        return;
+    }
+
+    n.Handlers[type] = eventHandler(value);
+    n.addEventListener(type, n.Handlers[type]);
+}
+
+function eventHandler(value) {
+    const compoHandler = /^callCompoHandler\(this, event, '([^']*)'\)$/.exec(value);
+
+    if (compoHandler) {
+        const fieldOrMethod = compoHandler[1];
+
+        return function (event) {
+            callCompoHandler(this, event, fieldOrMethod);
+        };
+    }
+
+    // Javascript handlers behave like inline handlers: returning false
+    // prevents the default action.
+    const f = new Function('event', value);
+
+    return function (event) {
+        if (f.call(this, event) === false) {
+            event.preventDefault();
+        }
+    };
+}
+
 function setText(change = {}) {
     const { NodeID, Value } = change;
 
@@ -189,7 +433,7 @@ function setText(change = {}) {
 }
 
 function appendChild(change = {}) {
-    const { NodeID, ChildID } = change;
+    const { NodeID, ChildID, Enter } = change;
 
     const n = goapp.nodes[NodeID];
     if (!n) {
@@ -206,11 +450,12 @@ function appendChild(change = {}) {
         return;
     }
 
-    n.appendChild(c)
+    containerOf(n).appendChild(c);
+    enter(c, Enter);
 }
 
 function removeChild(change = {}) {
-    const { NodeID, ChildID } = change;
+    const { NodeID, ChildID, Leave } = change;
 
     const n = goapp.nodes[NodeID];
     if (!n) {
@@ -222,11 +467,11 @@ function removeChild(change = {}) {
         return;
     }
 
-    n.removeChild(c);
+    leave(c, Leave, containerOf(n));
 }
 
 function replaceChild(change = {}) {
-    const { NodeID, ChildID, NewChildID } = change;
+    const { NodeID, ChildID, NewChildID, Enter, Leave } = change;
 
     const n = goapp.nodes[NodeID];
     if (!n) {
@@ -253,7 +498,115 @@ function replaceChild(change = {}) {
         return;
     }
 
-    n.replaceChild(nc, c);
+    const p = containerOf(n);
+
+    if (Leave) {
+        p.insertBefore(nc, c);
+        leave(c, Leave, p);
+    } else {
+        p.replaceChild(nc, c);
+    }
+
+    enter(nc, Enter);
+}
+
+function enter(node, transition) {
+    if (!transition || !node.classList) {
+        return;
+    }
+
+    // The enter class describes the initial state of the transition. It is
+    // removed once the node has been rendered.
+    const enterClass = transition + '-enter';
+    node.classList.add(enterClass);
+
+    requestAnimationFrame(() => {
+        requestAnimationFrame(() => {
+            node.classList.remove(enterClass);
+        });
+    });
+}
+
+function leave(node, transition, parent) {
+    const remove = () => {
+        if (node.parentNode === parent) {
+            parent.removeChild(node);
+        }
+    };
+
+    if (!transition || !node.classList) {
+        remove();
+        return;
+    }
+
+    node.classList.add(transition + '-leave');
+
+    const duration = transitionDuration(node);
+    if (duration === 0) {
+        remove();
+        return;
+    }
+
+    var removed = false;
+    const end = (event) => {
+        if (removed || (event && event.target !== node)) {
+            return;
+        }
+
+        removed = true;
+        remove();
+    };
+
+    node.addEventListener('transitionend', end);
+    node.addEventListener('animationend', end);
+
+    // Ensures the node is removed when the end events are not fired.
+    setTimeout(end, duration + 50);
+}
+
+function transitionDuration(node) {
+    const style = window.getComputedStyle(node);
+
+    const max = (durations, delays) => {
+        const d = durations.split(',').map(parseFloat);
+        const l = delays.split(',').map(parseFloat);
+
+        return Math.max(0, ...d.map((v, i) => (v + (l[i] || 0)) * 1000));
+    };
+
+    return Math.max(
+        max(style.transitionDuration, style.transitionDelay),
+        max(style.animationDuration, style.animationDelay)
+    );
+}
+
+function containerOf(node) {
+    return node.Portal || node;
+}
+
+function setStyle(change = {}) {
+    const { Key, Value = '' } = change;
+
+    var s = document.getElementById(Key);
+    if (!s) {
+        s = document.createElement('style');
+        s.id = Key;
+        s.type = 'text/css';
+        document.head.appendChild(s);
+    }
+
+    s.textContent = Value;
+}
+
+function delStyle(change = {}) {
+    const { Key } = change;
+
+    const s = document.getElementById(Key);
+    if (!s) {
+        return;
+    }
+
+    s.parentNode.removeChild(s);
 }
 
 function compoRoot(node) {
@@ -265,6 +618,77 @@ function compoRoot(node) {
     return compoRoot(n);
 }
 
+function callNode(nodeID, method, args = []) {
+    const n = compoRoot(goapp.nodes[nodeID]);
+    if (!n) {
+        throw 'node ' + nodeID + ' not found';
+    }
+
+    switch (method) {
+        case 'focus':
+            n.focus();
+            return null;
+
+        case 'blur':
+            n.blur();
+            return null;
+
+        case 'scrollIntoView':
+            n.scrollIntoView();
+            return null;
+
+        case 'boundingRect':
+            const rect = n.getBoundingClientRect();
+            return {
+                'X': rect.left,
+                'Y': rect.top,
+                'Width': rect.width,
+                'Height': rect.height
+            };
+
+        case 'setSelection':
+            n.setSelectionRange(args[0], args[1]);
+            return null;
+
+        default:
+            throw method + ' is not a supported node method';
+    }
+}
+
+function evalJS(script) {
+    const ret = eval(script);
+    return JSON.stringify(ret === undefined ? null : ret);
+}
+
+function callGoFunc(name, arg = null) {
+    return new Promise((resolve, reject) => {
+        const id = (++goapp.callCount).toString();
+        goapp.calls[id] = { resolve, reject };
+
+        golangCall(JSON.stringify({
+            'ID': id,
+            'Name': name,
+            'JSONArg': JSON.stringify(arg)
+        }));
+    });
+}
+
+function returnGoFunc(id, jsonRet, err) {
+    const call = goapp.calls[id];
+    if (!call) {
+        return;
+    }
+
+    delete goapp.calls[id];
+
+    if (err) {
+        call.reject(new Error(err));
+        return;
+    }
+
+    call.resolve(jsonRet ? JSON.parse(jsonRet) : null);
+}
+
 function mapObject(obj) {
     var map = {};
 
@@ -309,6 +733,15 @@ function callCompoHandler(elem, event, fieldOrMethod) {
             ondropToGolang(elem, event, fieldOrMethod);
             break;
 
+        case 'submit':
+            event.preventDefault();
+            submitToGolang(elem, event, fieldOrMethod);
+            break;
+
+        case 'scroll':
+            onscrollToGolang(elem, fieldOrMethod);
+            break;
+
         case 'contextmenu':
             event.preventDefault();
 
@@ -326,6 +759,36 @@ function onchangeToGolang(elem, fieldOrMethod) {
     }));
 }
 
+function submitToGolang(elem, event, fieldOrMethod) {
+    const payload = mapObject(event);
+    setPayloadSource(payload, elem);
+
+    golangRequest(JSON.stringify({
+        'CompoID': elem.CompoID,
+        'FieldOrMethod': fieldOrMethod,
+        'JSONValue': JSON.stringify(payload),
+        'Override': 'Submit'
+    }));
+}
+
+function onscrollToGolang(elem, fieldOrMethod) {
+    const payload = {
+        'ScrollTop': elem.scrollTop,
+        'ScrollLeft': elem.scrollLeft,
+        'ScrollHeight': elem.scrollHeight,
+        'ScrollWidth': elem.scrollWidth,
+        'ClientHeight': elem.clientHeight,
+        'ClientWidth': elem.clientWidth
+    };
+    setPayloadSource(payload, elem);
+
+    golangRequest(JSON.stringify({
+        'CompoID': elem.CompoID,
+        'FieldOrMethod': fieldOrMethod,
+        'JSONValue': JSON.stringify(payload)
+    }));
+}
+
 function onDragStartToGolang(elem, event, fieldOrMethod) {
     const payload = mapObject(event.dataTransfer);
     payload['Data'] = elem.dataset.drag;
@@ -1,9 +1,13 @@
 package dom
 
 import (
+	"regexp"
+	"strings"
 	"testing"
 
+	"github.com/murlokswarm/app"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestJsToGoHandler(t *testing.T) {
@@ -20,6 +24,58 @@ func TestJsToGoHandler(t *testing.T) {
 	assert.Equal(t, "callCompoHandler(this, event, 'OnChange')", v)
 }
 
+type Clickable struct {
+	Value string
+}
+
+func (c *Clickable) Render() string {
+	return `
+	<div>
+		<button onclick="Click">click</button>
+		<input onchange="Value">
+		<p onmouseover="js:console.log(event)">hello</p>
+	</div>`
+}
+
+func (c *Clickable) Click() {}
+
+func TestJsToGoHandlerBinding(t *testing.T) {
+	// The pattern used by page.js to bind component handlers with
+	// addEventListener, without evaluating them.
+	pattern := `^callCompoHandler\(this, event, '([^']*)'\)$`
+	require.Contains(t, jsTmpl, "/"+pattern+"/.exec(value)")
+	compoHandler := regexp.MustCompile(pattern)
+
+	f := app.NewFactory()
+	f.RegisterCompo(&Clickable{})
+
+	e := Engine{
+		Factory:        f,
+		AttrTransforms: []Transform{JsToGoHandler},
+	}
+	defer e.Close()
+
+	err := e.New(&Clickable{})
+	require.NoError(t, err)
+
+	// Handlers are synchronized as attributes that page.js binds.
+	handlers := make(map[string]string)
+	for _, n := range e.nodes {
+		for k, v := range n.Attrs {
+			if strings.HasPrefix(k, "on") {
+				handlers[k] = v
+			}
+		}
+	}
+	assert.NotContains(t, e.HTML(), " on")
+
+	require.Len(t, handlers, 3)
+	assert.Equal(t, []string{"Click"}, compoHandler.FindStringSubmatch(handlers["onclick"])[1:])
+	assert.Equal(t, []string{"Value"}, compoHandler.FindStringSubmatch(handlers["onchange"])[1:])
+	assert.False(t, compoHandler.MatchString(handlers["onmouseover"]))
+	assert.Contains(t, jsTmpl, "new Function('event', value)")
+}
+
 func TestHrefCompoFmt(t *testing.T) {
 	n, v := HrefCompoFmt("link", "hello")
 	assert.Equal(t, "link", n)
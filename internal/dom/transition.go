@@ -0,0 +1,44 @@
+package dom
+
+func (e *Engine) appendChildChange(parentID, childID string) {
+	e.changes = append(e.changes, change{
+		Action:  appendChild,
+		NodeID:  parentID,
+		ChildID: childID,
+		Enter:   e.transition(childID),
+	})
+}
+
+// removeChildChange must be called before the child is deleted.
+func (e *Engine) removeChildChange(parentID, childID string) {
+	e.changes = append(e.changes, change{
+		Action:  removeChild,
+		NodeID:  parentID,
+		ChildID: childID,
+		Leave:   e.transition(childID),
+	})
+}
+
+// replaceChildChange must be called before the replaced child is deleted.
+func (e *Engine) replaceChildChange(parentID, childID, newChildID string) {
+	e.changes = append(e.changes, change{
+		Action:     replaceChild,
+		NodeID:     parentID,
+		ChildID:    childID,
+		NewChildID: newChildID,
+		Enter:      e.transition(newChildID),
+		Leave:      e.transition(childID),
+	})
+}
+
+// transition returns the transition of the given node. The transition of a
+// component node is the one of its root.
+func (e *Engine) transition(nodeID string) string {
+	n, ok := e.nodes[nodeID]
+
+	for ok && n.IsCompo && len(n.ChildIDs) != 0 {
+		n, ok = e.nodes[n.ChildIDs[0]]
+	}
+
+	return n.Attrs["transition"]
+}
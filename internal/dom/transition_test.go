@@ -0,0 +1,96 @@
+package dom
+
+import (
+	"testing"
+
+	"github.com/murlokswarm/app"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Fader struct {
+	Items []string
+	Panel bool
+}
+
+func (f *Fader) Render() string {
+	return `
+	<div>
+		{{if .Panel}}
+			<dom.panel>
+		{{else}}
+			<span></span>
+		{{end}}
+
+		{{range .Items}}
+			<p transition="fade">{{.}}</p>
+		{{end}}
+	</div>
+	`
+}
+
+type Panel app.ZeroCompo
+
+func (p *Panel) Render() string {
+	return `<section transition="slide"></section>`
+}
+
+func TestEngineTransitions(t *testing.T) {
+	f := app.NewFactory()
+	f.RegisterCompo(&Fader{})
+	f.RegisterCompo(&Panel{})
+
+	var changes []change
+
+	e := Engine{
+		Factory: f,
+		Sync: func(v interface{}) error {
+			changes = append(changes, v.([]change)...)
+			return nil
+		},
+	}
+	defer e.Close()
+
+	find := func(a changeAction) change {
+		for _, c := range changes {
+			if c.Action == a && (len(c.Enter) != 0 || len(c.Leave) != 0) {
+				return c
+			}
+		}
+		return change{}
+	}
+
+	c := &Fader{}
+	err := e.New(c)
+	require.NoError(t, err)
+
+	changes = nil
+	c.Items = []string{"hello"}
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.Equal(t, "fade", find(appendChild).Enter)
+
+	changes = nil
+	c.Items = nil
+	err = e.Render(c)
+	require.NoError(t, err)
+	assert.Equal(t, "fade", find(removeChild).Leave)
+
+	changes = nil
+	c.Panel = true
+	err = e.Render(c)
+	require.NoError(t, err)
+
+	replace := find(replaceChild)
+	assert.Equal(t, "slide", replace.Enter)
+	assert.Empty(t, replace.Leave)
+
+	changes = nil
+	c.Panel = false
+	err = e.Render(c)
+	require.NoError(t, err)
+
+	replace = find(replaceChild)
+	assert.Empty(t, replace.Enter)
+	assert.Equal(t, "slide", replace.Leave)
+}
@@ -19,6 +19,7 @@ func TestDriver(t *testing.T, setup DriverSetup) {
 		assert.NotEmpty(t, d.AppName())
 		assert.NotEmpty(t, d.Resources())
 		assert.NotEmpty(t, d.Storage())
+		assert.NotNil(t, d.LocalStore())
 
 		tmp := d.NewWindow(app.WindowConfig{URL: "tests.Hello"})
 		if tmp.Err() == nil {
@@ -161,6 +162,15 @@ func testElemWithCompo(t *testing.T, e app.ElemWithCompo) {
 
 	e.Render(&Hello{})
 	assert.Error(t, e.Err())
+
+	h := &Hello{}
+	e.Mount(h)
+	assertElem(t, e)
+
+	if e.Err() == nil {
+		assert.Equal(t, h, e.Compo())
+		assert.True(t, e.Contains(h))
+	}
 }
 
 func testNavigator(t *testing.T, n app.Navigator, lazy bool) {
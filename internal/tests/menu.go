@@ -63,6 +63,15 @@ func testMenu(t *testing.T, m app.Menu) {
 	m.Render(&Menu{})
 	assert.Error(t, m.Err())
 
+	mc := &Menu{}
+	m.Mount(mc)
+	assertElem(t, m)
+
+	if m.Err() == nil {
+		assert.Equal(t, mc, m.Compo())
+		assert.True(t, m.Contains(mc))
+	}
+
 	assert.NotEmpty(t, m.Type())
 }
 
@@ -0,0 +1,140 @@
+package app
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ExposeFunc exposes the given function to the javascript of the pages and
+// windows under the given name.
+// f must be a func that takes at most one argument and that returns nothing,
+// a value, an error or a value and an error.
+//
+// In javascript, the function is called with callGoFunc(name, arg) which
+// returns a promise. The argument is converted to JSON and decoded into the
+// func argument. The promise is resolved with the returned value or rejected
+// with the returned error:
+//
+//	app.ExposeFunc("sum", func(n []int) int {
+//	    s := 0
+//	    for _, v := range n {
+//	        s += v
+//	    }
+//	    return s
+//	})
+//
+//	callGoFunc('sum', [21, 21]).then(s => console.log(s));
+//
+// It panics if f is not a valid func.
+func ExposeFunc(name string, f interface{}) {
+	if err := jsFuncs.Expose(name, f); err != nil {
+		Panicf("exposing func %s failed: %s", name, err)
+	}
+}
+
+// CallExposedFunc calls the named exposed function with the given JSON
+// encoded argument and returns its JSON encoded result.
+// It is used by drivers to respond to javascript calls and should be called
+// on the UI goroutine. A panic in the function is returned as an error.
+func CallExposedFunc(name, jsonArg string) (string, error) {
+	return jsFuncs.Call(name, jsonArg)
+}
+
+func newJSFuncRegistry() *jsFuncRegistry {
+	return &jsFuncRegistry{
+		funcs: make(map[string]reflect.Value),
+	}
+}
+
+type jsFuncRegistry struct {
+	mutex sync.RWMutex
+	funcs map[string]reflect.Value
+}
+
+func (r *jsFuncRegistry) Expose(name string, f interface{}) error {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func {
+		return errors.Errorf("%T is not a func", f)
+	}
+
+	t := v.Type()
+	if t.NumIn() > 1 {
+		return errors.New("func have more than 1 arg")
+	}
+
+	switch t.NumOut() {
+	case 0, 1:
+
+	case 2:
+		if t.Out(1) != errorType {
+			return errors.New("func second return value is not an error")
+		}
+
+	default:
+		return errors.New("func returns more than 2 values")
+	}
+
+	r.mutex.Lock()
+	r.funcs[name] = v
+	r.mutex.Unlock()
+	return nil
+}
+
+func (r *jsFuncRegistry) Call(name, jsonArg string) (res string, err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			res = ""
+			err = errors.Errorf("func %s panicked: %v", name, rec)
+		}
+	}()
+
+	r.mutex.RLock()
+	f, ok := r.funcs[name]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return "", errors.Errorf("func %s is not exposed", name)
+	}
+
+	t := f.Type()
+	var in []reflect.Value
+
+	if t.NumIn() == 1 {
+		arg := reflect.New(t.In(0))
+
+		if len(jsonArg) != 0 {
+			if err := json.Unmarshal([]byte(jsonArg), arg.Interface()); err != nil {
+				return "", errors.Wrapf(err, "decoding %s arg failed", name)
+			}
+		}
+
+		in = append(in, arg.Elem())
+	}
+
+	var ret interface{}
+
+	switch out := f.Call(in); len(out) {
+	case 1:
+		if t.Out(0) == errorType {
+			err, _ = out[0].Interface().(error)
+			break
+		}
+		ret = out[0].Interface()
+
+	case 2:
+		ret = out[0].Interface()
+		err, _ = out[1].Interface().(error)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(ret)
+	return string(b), err
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExposeFunc(t *testing.T) {
+	ExposeFunc("test", func() {})
+
+	defer func() { recover() }()
+	ExposeFunc("test", 42)
+	assert.Fail(t, "no panic")
+}
+
+func TestJSFuncRegistry(t *testing.T) {
+	r := newJSFuncRegistry()
+
+	require.NoError(t, r.Expose("noArg", func() {}))
+	require.NoError(t, r.Expose("sum", func(n []int) int {
+		s := 0
+		for _, v := range n {
+			s += v
+		}
+		return s
+	}))
+	require.NoError(t, r.Expose("err", func() error {
+		return errors.New("simulated err")
+	}))
+	require.NoError(t, r.Expose("valueErr", func(s string) (string, error) {
+		return "hello " + s, nil
+	}))
+	require.NoError(t, r.Expose("panic", func() {
+		panic("simulated panic")
+	}))
+
+	assert.Error(t, r.Expose("notFunc", "hello"))
+	assert.Error(t, r.Expose("tooManyArgs", func(a, b int) {}))
+	assert.Error(t, r.Expose("tooManyReturns", func() (int, int, error) { return 0, 0, nil }))
+	assert.Error(t, r.Expose("noErrReturn", func() (int, int) { return 0, 0 }))
+
+	tests := []struct {
+		scenario string
+		name     string
+		arg      string
+		ret      string
+		err      bool
+	}{
+		{
+			scenario: "func without arg",
+			name:     "noArg",
+			ret:      "null",
+		},
+		{
+			scenario: "func with arg",
+			name:     "sum",
+			arg:      "[21, 21]",
+			ret:      "42",
+		},
+		{
+			scenario: "func with value and error",
+			name:     "valueErr",
+			arg:      `"world"`,
+			ret:      `"hello world"`,
+		},
+		{
+			scenario: "func returns error",
+			name:     "err",
+			err:      true,
+		},
+		{
+			scenario: "func with bad arg",
+			name:     "sum",
+			arg:      `"hello"`,
+			err:      true,
+		},
+		{
+			scenario: "func panics",
+			name:     "panic",
+			err:      true,
+		},
+		{
+			scenario: "func not exposed",
+			name:     "unknown",
+			err:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			ret, err := r.Call(test.name, test.arg)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, test.ret, ret)
+		})
+	}
+}
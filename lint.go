@@ -0,0 +1,521 @@
+package app
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// LintError describes a mistake found in a component template.
+type LintError struct {
+	// The component name.
+	Compo string
+
+	// The template line where the mistake is located.
+	Line int
+
+	// The mistake description.
+	Msg string
+}
+
+func (e LintError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Compo, e.Line, e.Msg)
+}
+
+// LintErrors is a list of mistakes found in component templates.
+type LintErrors []LintError
+
+func (e LintErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+
+	return strings.Join(msgs, "\n")
+}
+
+// actionPlaceholder replaces the template actions when the html of a template
+// is checked. Attributes that contain it are not checked.
+const actionPlaceholder = "goapp-action"
+
+type linter struct {
+	name  string
+	typ   reflect.Type
+	tmpl  string
+	types map[string]reflect.Type
+
+	// Reports whether unregistered child components are reported.
+	children bool
+
+	// The depth of the current tag within svg and math elements, where tags
+	// are never components.
+	foreignDepth int
+
+	errs LintErrors
+}
+
+// lintCompo checks that the fields, methods, event handlers and child
+// components referenced in the template of the given component exist.
+// types contains the registered components.
+func lintCompo(c Compo, types map[string]reflect.Type, children bool) (errs LintErrors) {
+	l := &linter{
+		name:     CompoName(c),
+		typ:      reflect.TypeOf(c),
+		types:    types,
+		children: children,
+	}
+
+	defer func() {
+		// Components that can't be rendered in their zero state are not
+		// checked further. The mistakes found so far are kept.
+		if r := recover(); r != nil {
+			l.errs = append(l.errs, LintError{
+				Compo: l.name,
+				Line:  1,
+				Msg:   fmt.Sprintf("component can't be rendered in its zero state: %v", r),
+			})
+		}
+
+		sort.SliceStable(l.errs, func(i, j int) bool {
+			return l.errs[i].Line < l.errs[j].Line
+		})
+
+		errs = l.errs
+	}()
+
+	l.tmpl = c.Render()
+	l.lintTemplate()
+	l.lintHTML()
+	return l.errs
+}
+
+func (l *linter) errorf(pos int, format string, a ...interface{}) {
+	l.errs = append(l.errs, LintError{
+		Compo: l.name,
+		Line:  1 + strings.Count(l.tmpl[:pos], "\n"),
+		Msg:   fmt.Sprintf(format, a...),
+	})
+}
+
+func (l *linter) lintTemplate() {
+	t := parse.New(l.name)
+	t.Mode = parse.SkipFuncCheck
+
+	trees := make(map[string]*parse.Tree)
+
+	if _, err := t.Parse(l.tmpl, "", "", trees); err != nil {
+		l.errs = append(l.errs, LintError{
+			Compo: l.name,
+			Line:  1,
+			Msg:   err.Error(),
+		})
+		return
+	}
+
+	for _, tree := range trees {
+		if tree.Root != nil {
+			l.lintNode(tree.Root, true)
+		}
+	}
+}
+
+// lintNode checks the fields and methods referenced in the given node.
+// dotIsCompo reports whether the dot refers to the component.
+func (l *linter) lintNode(n parse.Node, dotIsCompo bool) {
+	switch n := n.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+
+		for _, c := range n.Nodes {
+			l.lintNode(c, dotIsCompo)
+		}
+
+	case *parse.ActionNode:
+		l.lintNode(n.Pipe, dotIsCompo)
+
+	case *parse.IfNode:
+		l.lintBranch(&n.BranchNode, dotIsCompo, dotIsCompo)
+
+	case *parse.WithNode:
+		l.lintBranch(&n.BranchNode, dotIsCompo, false)
+
+	case *parse.RangeNode:
+		l.lintBranch(&n.BranchNode, dotIsCompo, false)
+
+	case *parse.TemplateNode:
+		if n.Pipe != nil {
+			l.lintNode(n.Pipe, dotIsCompo)
+		}
+
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+
+		for _, c := range n.Cmds {
+			l.lintNode(c, dotIsCompo)
+		}
+
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			l.lintNode(arg, dotIsCompo)
+		}
+
+	case *parse.FieldNode:
+		if dotIsCompo {
+			l.lintPath(int(n.Pos), n.Ident)
+		}
+
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			l.lintPath(int(n.Pos), n.Ident[1:])
+		}
+	}
+}
+
+func (l *linter) lintBranch(n *parse.BranchNode, dotIsCompo, innerDotIsCompo bool) {
+	l.lintNode(n.Pipe, dotIsCompo)
+	l.lintNode(n.List, innerDotIsCompo)
+	l.lintNode(n.ElseList, dotIsCompo)
+}
+
+// lintPath checks that the given field or method path can be evaluated from
+// the component.
+func (l *linter) lintPath(pos int, path []string) {
+	t := l.typ
+
+	for i, name := range path {
+		next, ok := lookup(t, name)
+		if !ok {
+			l.errorf(pos, "%s is not a field or method of %s",
+				strings.Join(path[:i+1], "."),
+				t,
+			)
+			return
+		}
+
+		if next == nil {
+			return
+		}
+		t = next
+	}
+}
+
+// lookup returns the type of the named field or of the first value returned
+// by the named method. It returns a nil type when the type of the value can't
+// be determined statically.
+func lookup(t reflect.Type, name string) (reflect.Type, bool) {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map:
+		return nil, true
+	}
+
+	if m, ok := t.MethodByName(name); ok {
+		if m.Type.NumOut() == 0 {
+			return nil, true
+		}
+		return m.Type.Out(0), true
+	}
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Interface, reflect.Map:
+		return nil, true
+
+	case reflect.Struct:
+		if f, ok := t.FieldByName(name); ok && len(f.PkgPath) == 0 {
+			return f.Type, true
+		}
+	}
+
+	return nil, false
+}
+
+// lintHTML checks the event handlers and the child components of the template.
+func (l *linter) lintHTML() {
+	markup, offsets := stripActions(l.tmpl)
+	z := html.NewTokenizer(strings.NewReader(markup))
+	pos := 0
+
+	for {
+		tt := z.Next()
+		start := pos
+		pos += len(z.Raw())
+
+		switch tt {
+		case html.ErrorToken:
+			return
+
+		case html.EndTagToken:
+			if name, _ := z.TagName(); l.foreignDepth > 0 && isForeignTag(string(name)) {
+				l.foreignDepth--
+			}
+
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			if tt == html.StartTagToken && isForeignTag(string(name)) {
+				l.foreignDepth++
+			}
+
+			var attrs [][2]string
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				attrs = append(attrs, [2]string{string(k), string(v)})
+			}
+
+			l.lintTag(offsets(start), string(name), attrs)
+		}
+	}
+}
+
+func (l *linter) lintTag(pos int, name string, attrs [][2]string) {
+	if strings.Contains(name, actionPlaceholder) {
+		return
+	}
+
+	if l.foreignDepth == 0 && name != "portal" && atom.Lookup([]byte(name)) == 0 {
+		l.lintCompoTag(pos, name, attrs)
+		return
+	}
+
+	for _, a := range attrs {
+		k, v := a[0], a[1]
+
+		if !strings.HasPrefix(k, "on") || strings.Contains(k, actionPlaceholder) {
+			continue
+		}
+
+		if _, ok := domEvents[strings.TrimPrefix(k, "on")]; !ok {
+			l.errorf(pos, "%s is not an event handler attribute", k)
+		}
+
+		if strings.HasPrefix(v, "js:") || strings.Contains(v, actionPlaceholder) {
+			continue
+		}
+
+		if len(v) == 0 {
+			l.errorf(pos, "%s handler is empty", k)
+			continue
+		}
+
+		path := strings.Split(v, ".")
+		if _, ok := lookup(l.typ, path[0]); !ok {
+			l.errorf(pos, "%s handler %s is not a field or method of %s", k, v, l.typ)
+		}
+	}
+}
+
+func isForeignTag(name string) bool {
+	return name == "svg" || name == "math"
+}
+
+func (l *linter) lintCompoTag(pos int, name string, attrs [][2]string) {
+	t, ok := l.types[name]
+	if !ok {
+		if l.children {
+			l.errorf(pos, "component %s is not imported", name)
+		}
+		return
+	}
+
+	fields := make(map[string]struct{}, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); len(f.PkgPath) == 0 && !f.Anonymous {
+			fields[strings.ToLower(f.Name)] = struct{}{}
+		}
+	}
+
+	for _, a := range attrs {
+		k := a[0]
+
+		if strings.Contains(k, actionPlaceholder) {
+			continue
+		}
+
+		if _, ok := fields[k]; !ok {
+			l.errorf(pos, "%s is not a field of component %s", k, name)
+		}
+	}
+}
+
+// stripActions replaces the template actions with a placeholder. It returns
+// the resulting markup and a func that converts a markup offset to a template
+// offset.
+func stripActions(tmpl string) (string, func(int) int) {
+	var b strings.Builder
+
+	// Pairs of markup offset and of the number of bytes removed before it.
+	var shifts [][2]int
+	i := 0
+
+	for {
+		start := strings.Index(tmpl[i:], "{{")
+		if start < 0 {
+			break
+		}
+		start += i
+
+		end := strings.Index(tmpl[start:], "}}")
+		if end < 0 {
+			break
+		}
+		end += start + 2
+
+		b.WriteString(tmpl[i:start])
+		b.WriteString(actionPlaceholder)
+		shifts = append(shifts, [2]int{b.Len(), end - start - len(actionPlaceholder)})
+		i = end
+	}
+
+	b.WriteString(tmpl[i:])
+
+	return b.String(), func(offset int) int {
+		for _, s := range shifts {
+			if s[0] > offset {
+				break
+			}
+			offset += s[1]
+		}
+		return offset
+	}
+}
+
+var domEvents = map[string]struct{}{
+	"abort":                    {},
+	"afterprint":               {},
+	"animationcancel":          {},
+	"animationend":             {},
+	"animationiteration":       {},
+	"animationstart":           {},
+	"auxclick":                 {},
+	"beforecopy":               {},
+	"beforecut":                {},
+	"beforeinput":              {},
+	"beforepaste":              {},
+	"beforeprint":              {},
+	"beforeunload":             {},
+	"blur":                     {},
+	"cancel":                   {},
+	"canplay":                  {},
+	"canplaythrough":           {},
+	"change":                   {},
+	"click":                    {},
+	"close":                    {},
+	"compositionend":           {},
+	"compositionstart":         {},
+	"compositionupdate":        {},
+	"contextmenu":              {},
+	"copy":                     {},
+	"cuechange":                {},
+	"cut":                      {},
+	"dblclick":                 {},
+	"drag":                     {},
+	"dragend":                  {},
+	"dragenter":                {},
+	"dragexit":                 {},
+	"dragleave":                {},
+	"dragover":                 {},
+	"dragstart":                {},
+	"drop":                     {},
+	"durationchange":           {},
+	"emptied":                  {},
+	"encrypted":                {},
+	"ended":                    {},
+	"error":                    {},
+	"focus":                    {},
+	"focusin":                  {},
+	"focusout":                 {},
+	"formdata":                 {},
+	"fullscreenchange":         {},
+	"fullscreenerror":          {},
+	"gotpointercapture":        {},
+	"hashchange":               {},
+	"input":                    {},
+	"invalid":                  {},
+	"keydown":                  {},
+	"keypress":                 {},
+	"keyup":                    {},
+	"languagechange":           {},
+	"load":                     {},
+	"loadeddata":               {},
+	"loadedmetadata":           {},
+	"loadend":                  {},
+	"loadstart":                {},
+	"lostpointercapture":       {},
+	"message":                  {},
+	"messageerror":             {},
+	"mousedown":                {},
+	"mouseenter":               {},
+	"mouseleave":               {},
+	"mousemove":                {},
+	"mouseout":                 {},
+	"mouseover":                {},
+	"mouseup":                  {},
+	"mousewheel":               {},
+	"offline":                  {},
+	"online":                   {},
+	"pagehide":                 {},
+	"pageshow":                 {},
+	"paste":                    {},
+	"pause":                    {},
+	"play":                     {},
+	"playing":                  {},
+	"pointercancel":            {},
+	"pointerdown":              {},
+	"pointerenter":             {},
+	"pointerleave":             {},
+	"pointermove":              {},
+	"pointerout":               {},
+	"pointerover":              {},
+	"pointerup":                {},
+	"popstate":                 {},
+	"progress":                 {},
+	"ratechange":               {},
+	"rejectionhandled":         {},
+	"reset":                    {},
+	"resize":                   {},
+	"scroll":                   {},
+	"search":                   {},
+	"securitypolicyviolation":  {},
+	"seeked":                   {},
+	"seeking":                  {},
+	"select":                   {},
+	"selectionchange":          {},
+	"selectstart":              {},
+	"show":                     {},
+	"slotchange":               {},
+	"stalled":                  {},
+	"storage":                  {},
+	"submit":                   {},
+	"suspend":                  {},
+	"timeupdate":               {},
+	"toggle":                   {},
+	"touchcancel":              {},
+	"touchend":                 {},
+	"touchmove":                {},
+	"touchstart":               {},
+	"transitioncancel":         {},
+	"transitionend":            {},
+	"transitionrun":            {},
+	"transitionstart":          {},
+	"unhandledrejection":       {},
+	"unload":                   {},
+	"volumechange":             {},
+	"waiting":                  {},
+	"webkitanimationend":       {},
+	"webkitanimationiteration": {},
+	"webkitanimationstart":     {},
+	"webkittransitionend":      {},
+	"wheel":                    {},
+}
@@ -0,0 +1,219 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type LintedChild struct {
+	Title string
+	Count int
+}
+
+func (c *LintedChild) Render() string {
+	return `<p>{{.Title}}</p>`
+}
+
+type Linted struct {
+	Form
+
+	Name    string
+	Items   []string
+	Child   LintedChild
+	Tmpl    string
+	Handler string
+}
+
+func (l *Linted) Render() string {
+	if len(l.Tmpl) != 0 {
+		return l.Tmpl
+	}
+
+	return `
+	<div>
+		<h1 onclick="Click">{{.Name}} {{.Child.Title}} {{.Greeting.Len}}</h1>
+		<input onchange="Name" onkeyup="js:console.log(event)" {{.Handler}}>
+		{{range .Items}}
+			<p>{{.}} {{$.Name}}</p>
+		{{end}}
+		{{with .Child}}{{.Count}}{{end}}
+		<app.lintedchild title="{{.Name}}" count="42">
+		<svg><path d="M0 0"></path></svg>
+		<p>{{.FieldErr "Name"}}</p>
+	</div>
+	`
+}
+
+func (l *Linted) Click() {}
+
+func (l *Linted) Greeting() Greeting {
+	return Greeting(l.Name)
+}
+
+type Greeting string
+
+func (g Greeting) Len() int {
+	return len(g)
+}
+
+func TestLintCompo(t *testing.T) {
+	tests := []struct {
+		scenario string
+		tmpl     string
+		errs     []string
+	}{
+		{
+			scenario: "valid template",
+		},
+		{
+			scenario: "unknown field",
+			tmpl: `
+			<p>
+				{{.Nam}}
+			</p>`,
+			errs: []string{"app.linted:3: Nam is not a field or method of *app.Linted"},
+		},
+		{
+			scenario: "unknown nested field",
+			tmpl:     `<p>{{if .Name}}{{.Child.Titel}}{{end}}</p>`,
+			errs:     []string{"app.linted:1: Child.Titel is not a field or method of app.LintedChild"},
+		},
+		{
+			scenario: "unknown root variable field",
+			tmpl:     `<p>{{range .Items}}{{$.Nam}}{{end}}</p>`,
+			errs:     []string{"app.linted:1: Nam is not a field or method of *app.Linted"},
+		},
+		{
+			scenario: "unexported field",
+			tmpl:     `<p>{{.errs}}</p>`,
+			errs:     []string{"app.linted:1: errs is not a field or method of *app.Linted"},
+		},
+		{
+			scenario: "unknown handler",
+			tmpl: `<div>
+				<p>{{.Name}}</p>
+				<button onclick="Clik">
+			</div>`,
+			errs: []string{"app.linted:3: onclick handler Clik is not a field or method of *app.Linted"},
+		},
+		{
+			scenario: "unknown event",
+			tmpl:     `<button onclik="Click">`,
+			errs:     []string{"app.linted:1: onclik is not an event handler attribute"},
+		},
+		{
+			scenario: "pointer and media events",
+			tmpl:     `<video onpointerover="Click" onloadedmetadata="Click" onseeking="Click" onbeforeinput="Click" ongotpointercapture="Click">`,
+		},
+		{
+			scenario: "empty handler",
+			tmpl:     `<button onclick="">`,
+			errs:     []string{"app.linted:1: onclick handler is empty"},
+		},
+		{
+			scenario: "unknown child component attribute",
+			tmpl:     `<div><app.lintedchild titel="hello"></div>`,
+			errs:     []string{"app.linted:1: titel is not a field of component app.lintedchild"},
+		},
+		{
+			scenario: "template syntax error",
+			tmpl:     `<p>{{.Name}</p>`,
+			errs:     []string{"app.linted:1: template: app.linted:1: bad character U+007D '}'"},
+		},
+	}
+
+	types := map[string]reflect.Type{
+		"app.lintedchild": reflect.TypeOf(LintedChild{}),
+	}
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			errs := lintCompo(&Linted{Tmpl: test.tmpl}, types, false)
+
+			var msgs []string
+			for _, err := range errs {
+				msgs = append(msgs, err.Error())
+			}
+
+			assert.Equal(t, test.errs, msgs)
+		})
+	}
+}
+
+type BadLinted struct {
+	Name string
+}
+
+func (b *BadLinted) Render() string {
+	return `<p>{{.Nam}}</p>`
+}
+
+type PanicLinted struct {
+	Items []string
+}
+
+func (p *PanicLinted) Render() string {
+	return p.Items[0]
+}
+
+func TestLintCompoZeroStatePanic(t *testing.T) {
+	errs := lintCompo(&PanicLinted{}, nil, false)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "app.paniclinted", errs[0].Compo)
+	assert.Equal(t, 1, errs[0].Line)
+	assert.Contains(t, errs[0].Msg, "component can't be rendered in its zero state")
+}
+
+func TestFactoryLint(t *testing.T) {
+	f := NewFactory()
+
+	name, err := f.RegisterCompo(&BadLinted{})
+	require.NoError(t, err)
+	assert.Equal(t, "app.badlinted", name)
+	assert.True(t, f.IsCompoRegistered(name))
+
+	err = f.Lint()
+	require.Error(t, err)
+
+	errs, ok := err.(LintErrors)
+	require.True(t, ok)
+	require.Len(t, errs, 1)
+	assert.Equal(t, LintError{
+		Compo: "app.badlinted",
+		Line:  1,
+		Msg:   "Nam is not a field or method of *app.BadLinted",
+	}, errs[0])
+
+	f = NewFactory()
+
+	_, err = f.RegisterCompo(&Linted{})
+	require.NoError(t, err)
+
+	err = f.Lint()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "component app.lintedchild is not imported")
+
+	_, err = f.RegisterCompo(&LintedChild{})
+	require.NoError(t, err)
+	assert.NoError(t, f.Lint())
+}
+
+type FactoryLinted struct {
+	Registered bool
+}
+
+func (f *FactoryLinted) Render() string {
+	lintedFactory.IsCompoRegistered("app.factorylinted")
+	return `<div>{{.Registered}}</div>`
+}
+
+var lintedFactory = NewFactory()
+
+func TestFactoryLintUsesFactory(t *testing.T) {
+	_, err := lintedFactory.RegisterCompo(&FactoryLinted{})
+	require.NoError(t, err)
+	assert.NoError(t, lintedFactory.Lint())
+}
@@ -0,0 +1,161 @@
+package app
+
+import (
+	"strings"
+)
+
+const defaultOverscan = 3
+
+// VirtualList is a component that displays a large number of rows by only
+// rendering the ones that are visible.
+//
+// Rows are rendered by the component named in the row attribute. Its Index
+// field is set to the index of the row to render and its Source field, if
+// any, to the list source attribute:
+//
+//	<app.virtuallist row="main.contactrow" source="contacts" count="50000"
+//	                 rowheight="32" height="480">
+//
+// Row components are reused when the list is scrolled: their index is updated
+// and they are rendered again.
+// VirtualList is imported by default.
+type VirtualList struct {
+	// The name of the component that renders a row.
+	Row string
+
+	// An optional value passed to the row components.
+	Source string
+
+	// The number of rows.
+	Count int
+
+	// The height of a row in pixels.
+	RowHeight int
+
+	// The height of the list in pixels.
+	Height int
+
+	// The number of rows rendered above and below the visible ones.
+	// Defaults to 3.
+	Overscan int
+
+	scrollTop int
+	viewport  int
+}
+
+// VirtualRow describes a row rendered by a virtual list.
+type VirtualRow struct {
+	Index int
+	Top   int
+}
+
+// Render satisfies the Compo interface.
+func (l *VirtualList) Render() string {
+	row := ""
+	if isCompoName(l.Row) {
+		row = `<` + l.Row + ` index="{{.Index}}" source="{{$.Source}}">`
+	}
+
+	return `
+	<div class="app-virtuallist" style="height: {{.Height}}px; overflow-y: auto;" onscroll="Scroll">
+		<div style="position: relative; height: {{.TotalHeight}}px;">
+			{{range .Rows}}
+				<div style="position: absolute; left: 0; right: 0; top: {{.Top}}px; height: {{$.RowHeight}}px;">
+					` + row + `
+				</div>
+			{{end}}
+		</div>
+	</div>
+	`
+}
+
+// TotalHeight returns the height in pixels of all the rows.
+func (l *VirtualList) TotalHeight() int {
+	return l.Count * l.RowHeight
+}
+
+// Rows returns the rows to render.
+// Rows are ordered by position in the rendered window rather than by index so
+// the same row components are reused when the list is scrolled.
+func (l *VirtualList) Rows() []VirtualRow {
+	first, last := l.visibleRows()
+	if first == last {
+		return nil
+	}
+
+	rows := make([]VirtualRow, last-first)
+	size := len(rows)
+
+	for i := first; i < last; i++ {
+		r := &rows[i%size]
+		r.Index = i
+		r.Top = i * l.RowHeight
+	}
+
+	return rows
+}
+
+// Scroll is the handler called when the list is scrolled.
+// The list is rendered when the visible rows change.
+func (l *VirtualList) Scroll(e ScrollEvent) {
+	if l.scroll(e) {
+		Render(l)
+	}
+}
+
+func (l *VirtualList) scroll(e ScrollEvent) bool {
+	first, last := l.visibleRows()
+
+	l.scrollTop = int(e.ScrollTop)
+	if e.ClientHeight > 0 {
+		l.viewport = int(e.ClientHeight)
+	}
+
+	newFirst, newLast := l.visibleRows()
+	return newFirst != first || newLast != last
+}
+
+func (l *VirtualList) visibleRows() (first, last int) {
+	if l.RowHeight <= 0 || l.Count <= 0 {
+		return 0, 0
+	}
+
+	viewport := l.viewport
+	if viewport == 0 {
+		viewport = l.Height
+	}
+
+	overscan := l.Overscan
+	if overscan <= 0 {
+		overscan = defaultOverscan
+	}
+
+	first = l.scrollTop/l.RowHeight - overscan
+	if first < 0 {
+		first = 0
+	}
+
+	last = (l.scrollTop+viewport)/l.RowHeight + 1 + overscan
+	if last > l.Count {
+		last = l.Count
+	}
+
+	if first > last {
+		first = last
+	}
+
+	return first, last
+}
+
+func isCompoName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	return strings.IndexFunc(name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' ||
+			r >= 'A' && r <= 'Z' ||
+			r >= '0' && r <= '9' ||
+			r == '.' || r == '_' || r == '-')
+	}) < 0
+}
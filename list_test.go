@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualListRows(t *testing.T) {
+	l := &VirtualList{
+		Row:       "app.row",
+		Count:     1000,
+		RowHeight: 10,
+		Height:    100,
+	}
+
+	rows := l.Rows()
+	assert.Len(t, rows, 14)
+	assert.Equal(t, 0, rows[0].Index)
+	assert.Equal(t, 10000, l.TotalHeight())
+
+	assert.False(t, l.scroll(ScrollEvent{ScrollTop: 5}))
+	assert.True(t, l.scroll(ScrollEvent{ScrollTop: 500, ClientHeight: 200}))
+
+	rows = l.Rows()
+	assert.Len(t, rows, 27)
+
+	for _, r := range rows {
+		assert.True(t, r.Index >= 47 && r.Index < 74)
+		assert.Equal(t, r.Index*10, r.Top)
+	}
+
+	assert.True(t, l.scroll(ScrollEvent{ScrollTop: 100000}))
+	assert.Empty(t, l.Rows())
+}
+
+func TestVirtualListRowsReuse(t *testing.T) {
+	l := &VirtualList{
+		Count:     1000,
+		RowHeight: 10,
+		Height:    100,
+		Overscan:  1,
+	}
+
+	l.scroll(ScrollEvent{ScrollTop: 100})
+	before := l.Rows()
+
+	l.scroll(ScrollEvent{ScrollTop: 110})
+	after := l.Rows()
+
+	changed := 0
+	for i := range before {
+		if before[i] != after[i] {
+			changed++
+		}
+	}
+
+	assert.Equal(t, 1, changed)
+}
+
+func TestIsCompoName(t *testing.T) {
+	assert.True(t, isCompoName("main.contact_row"))
+	assert.False(t, isCompoName(""))
+	assert.False(t, isCompoName(`main.row" onclick="x`))
+	assert.False(t, isCompoName("{{.Secret}}"))
+}
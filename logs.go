@@ -251,6 +251,24 @@ func (w *windowWithLogs) Load(url string, v ...interface{}) {
 	}
 }
 
+func (w *windowWithLogs) Mount(c Compo) {
+	WhenDebug(func() {
+		Logf("window %s is mounting %T",
+			w.ID(),
+			c,
+		)
+	})
+
+	w.Window.Mount(c)
+	if w.Err() != nil {
+		Logf("window %s failed to mount %T: %s",
+			w.ID(),
+			c,
+			w.Err(),
+		)
+	}
+}
+
 func (w *windowWithLogs) Render(c Compo) {
 	WhenDebug(func() {
 		Logf("window %s is rendering %T",
@@ -269,6 +287,43 @@ func (w *windowWithLogs) Render(c Compo) {
 	}
 }
 
+func (w *windowWithLogs) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	WhenDebug(func() {
+		Logf("window %s is calling %s on node %s",
+			w.ID(),
+			method,
+			nodeID,
+		)
+	})
+
+	err := w.Window.CallNode(nodeID, method, out, args...)
+	if err != nil {
+		Logf("window %s failed to call %s on node %s: %s",
+			w.ID(),
+			method,
+			nodeID,
+			err,
+		)
+	}
+	return err
+}
+
+func (w *windowWithLogs) EvalJS(script string, out interface{}) error {
+	WhenDebug(func() {
+		Logf("window %s is evaluating %q", w.ID(), script)
+	})
+
+	err := evalJS(w.Window, script, out)
+	if err != nil {
+		Logf("window %s failed to evaluate %q: %s",
+			w.ID(),
+			script,
+			err,
+		)
+	}
+	return err
+}
+
 func (w *windowWithLogs) Reload() {
 	WhenDebug(func() {
 		Logf("window %s is reloading", w.ID())
@@ -449,6 +504,24 @@ func (p *pageWithLogs) Load(url string, v ...interface{}) {
 	}
 }
 
+func (p *pageWithLogs) Mount(c Compo) {
+	WhenDebug(func() {
+		Logf("page %s is mounting %T",
+			p.ID(),
+			c,
+		)
+	})
+
+	p.Page.Mount(c)
+	if p.Err() != nil {
+		Logf("page %s failed to mount %T: %s",
+			p.ID(),
+			c,
+			p.Err(),
+		)
+	}
+}
+
 func (p *pageWithLogs) Render(c Compo) {
 	WhenDebug(func() {
 		Logf("page %s is rendering %T",
@@ -467,6 +540,43 @@ func (p *pageWithLogs) Render(c Compo) {
 	}
 }
 
+func (p *pageWithLogs) CallNode(nodeID, method string, out interface{}, args ...interface{}) error {
+	WhenDebug(func() {
+		Logf("page %s is calling %s on node %s",
+			p.ID(),
+			method,
+			nodeID,
+		)
+	})
+
+	err := p.Page.CallNode(nodeID, method, out, args...)
+	if err != nil {
+		Logf("page %s failed to call %s on node %s: %s",
+			p.ID(),
+			method,
+			nodeID,
+			err,
+		)
+	}
+	return err
+}
+
+func (p *pageWithLogs) EvalJS(script string, out interface{}) error {
+	WhenDebug(func() {
+		Logf("page %s is evaluating %q", p.ID(), script)
+	})
+
+	err := evalJS(p.Page, script, out)
+	if err != nil {
+		Logf("page %s failed to evaluate %q: %s",
+			p.ID(),
+			script,
+			err,
+		)
+	}
+	return err
+}
+
 func (p *pageWithLogs) Reload() {
 	WhenDebug(func() {
 		Logf("page %s is reloading", p.ID())
@@ -524,6 +634,26 @@ func (m *menuWithLogs) Load(url string, v ...interface{}) {
 	}
 }
 
+func (m *menuWithLogs) Mount(c Compo) {
+	WhenDebug(func() {
+		Logf("%s %s is mounting %T",
+			m.Type(),
+			m.ID(),
+			c,
+		)
+	})
+
+	m.Menu.Mount(c)
+	if m.Err() != nil {
+		Logf("%s %s failed to mount %T: %s",
+			m.Type(),
+			m.ID(),
+			c,
+			m.Err(),
+		)
+	}
+}
+
 func (m *menuWithLogs) Render(c Compo) {
 	WhenDebug(func() {
 		Logf("%s %s is rendering %T",
@@ -569,6 +699,20 @@ func (d *dockWithLogs) Load(url string, v ...interface{}) {
 	}
 }
 
+func (d *dockWithLogs) Mount(c Compo) {
+	WhenDebug(func() {
+		Logf("dock tile is mounting %T", c)
+	})
+
+	d.DockTile.Mount(c)
+	if d.Err() != nil {
+		Logf("dock tile failed to mount %T: %s",
+			c,
+			d.Err(),
+		)
+	}
+}
+
 func (d *dockWithLogs) Render(c Compo) {
 	WhenDebug(func() {
 		Logf("dock tile is rendering %T", c)
@@ -634,6 +778,24 @@ func (s *statusMenuWithLogs) Load(url string, v ...interface{}) {
 	}
 }
 
+func (s *statusMenuWithLogs) Mount(c Compo) {
+	WhenDebug(func() {
+		Logf("status menu %s is mounting %T",
+			s.ID(),
+			c,
+		)
+	})
+
+	s.StatusMenu.Mount(c)
+	if s.Err() != nil {
+		Logf("status menu %s failed to mount %T: %s",
+			s.ID(),
+			c,
+			s.Err(),
+		)
+	}
+}
+
 func (s *statusMenuWithLogs) Render(c Compo) {
 	WhenDebug(func() {
 		Logf("status menu %s is rendering %T",
@@ -704,3 +866,10 @@ func prettyConf(c interface{}) string {
 	b, _ := json.MarshalIndent(c, "", "    ")
 	return string(b)
 }
+
+func evalJS(e Elem, script string, out interface{}) error {
+	if evaluator, ok := e.(JSEvaluator); ok {
+		return evaluator.EvalJS(script, out)
+	}
+	return ErrNotSupported
+}
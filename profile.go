@@ -0,0 +1,348 @@
+package app
+
+import (
+	"container/heap"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RenderProfile describes the performance of a component rendering.
+type RenderProfile struct {
+	// The name of the rendered component.
+	Compo string
+
+	// The time when the rendering started.
+	Start time.Time
+
+	// The duration of the whole rendering, synchronization included.
+	Duration time.Duration
+
+	// The template executions of the rendered component and of the
+	// components it has rendered.
+	Templates []TemplateProfile
+
+	// The number of nodes created, changed and deleted.
+	Created int
+	Changed int
+	Deleted int
+
+	// The size in bytes of the changes synchronized with the remote dom.
+	SyncSize int
+
+	// The duration of the synchronization with the remote dom.
+	SyncDuration time.Duration
+}
+
+// TemplateProfile describes the performance of a component template
+// execution.
+type TemplateProfile struct {
+	Compo    string
+	Start    time.Time
+	Duration time.Duration
+}
+
+// ProfileRenders calls f with the profile of each component rendering until
+// the returned func is called.
+// f is called on the UI goroutine and should return quickly.
+func ProfileRenders(f func(RenderProfile)) (stop func()) {
+	return profilers.Add(f)
+}
+
+// Profiling reports whether component renderings are profiled.
+func Profiling() bool {
+	return profilers.Len() != 0
+}
+
+// ReportRenderProfile reports the given profile to the funcs registered with
+// ProfileRenders. It is used by drivers.
+func ReportRenderProfile(p RenderProfile) {
+	profilers.Report(p)
+}
+
+type profilerRegistry struct {
+	mutex     sync.RWMutex
+	profilers map[string]func(RenderProfile)
+}
+
+func newProfilerRegistry() *profilerRegistry {
+	return &profilerRegistry{
+		profilers: make(map[string]func(RenderProfile)),
+	}
+}
+
+func (r *profilerRegistry) Add(f func(RenderProfile)) func() {
+	id := uuid.New().String()
+
+	r.mutex.Lock()
+	r.profilers[id] = f
+	r.mutex.Unlock()
+
+	return func() {
+		r.mutex.Lock()
+		delete(r.profilers, id)
+		r.mutex.Unlock()
+	}
+}
+
+func (r *profilerRegistry) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.profilers)
+}
+
+func (r *profilerRegistry) Report(p RenderProfile) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	for _, f := range r.profilers {
+		f(p)
+	}
+}
+
+// ProfilerConfig describes the render profiler addon configuration.
+type ProfilerConfig struct {
+	// The duration above which renders are logged.
+	// Defaults to 16ms, the duration of a frame at 60 frames per second.
+	Threshold time.Duration
+
+	// The number of slowest renders logged when the app stops running.
+	// Defaults to 10.
+	Slowest int
+
+	// The path of a file where the renders are written when the app stops
+	// running. The file uses the trace event format that can be opened with
+	// trace viewers like chrome://tracing.
+	// No file is written when the path is empty.
+	TraceFile string
+
+	// The maximum number of renders written in the trace file. Older renders
+	// are discarded.
+	// Defaults to 10000.
+	TraceSize int
+}
+
+// Profiler returns an addon that profiles component renderings. It logs the
+// renders that are slower than the configured threshold and the slowest
+// renders when the app stops running.
+func Profiler(c ProfilerConfig) func(Driver) Driver {
+	if c.Threshold <= 0 {
+		c.Threshold = time.Millisecond * 16
+	}
+
+	if c.Slowest <= 0 {
+		c.Slowest = 10
+	}
+
+	if c.TraceSize <= 0 {
+		c.TraceSize = 10000
+	}
+
+	return func(d Driver) Driver {
+		return &driverWithProfiler{
+			Driver: d,
+			config: c,
+		}
+	}
+}
+
+type driverWithProfiler struct {
+	Driver
+
+	config  ProfilerConfig
+	mutex   sync.Mutex
+	slowest profileHeap
+
+	// The renders written in the trace file. It is used as a ring buffer
+	// where next is the index of the oldest render once it is full.
+	traced []RenderProfile
+	next   int
+}
+
+func (d *driverWithProfiler) Run(f *Factory) error {
+	stop := ProfileRenders(d.profile)
+
+	err := d.Driver.Run(f)
+
+	stop()
+	d.report()
+	return err
+}
+
+func (d *driverWithProfiler) profile(p RenderProfile) {
+	if p.Duration >= d.config.Threshold {
+		Logf("slow render: %s", formatProfile(p))
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.slowest) < d.config.Slowest {
+		heap.Push(&d.slowest, p)
+	} else if p.Duration > d.slowest[0].Duration {
+		d.slowest[0] = p
+		heap.Fix(&d.slowest, 0)
+	}
+
+	if len(d.config.TraceFile) == 0 {
+		return
+	}
+
+	if len(d.traced) < d.config.TraceSize {
+		d.traced = append(d.traced, p)
+		return
+	}
+
+	d.traced[d.next] = p
+	d.next = (d.next + 1) % len(d.traced)
+}
+
+func (d *driverWithProfiler) report() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if len(d.config.TraceFile) != 0 {
+		traced := make([]RenderProfile, 0, len(d.traced))
+		traced = append(traced, d.traced[d.next:]...)
+		traced = append(traced, d.traced[:d.next]...)
+
+		if err := writeTrace(d.config.TraceFile, traced); err != nil {
+			Logf("writing render trace failed: %s", err)
+		}
+	}
+
+	slowest := make([]RenderProfile, len(d.slowest))
+	copy(slowest, d.slowest)
+
+	sort.SliceStable(slowest, func(i, j int) bool {
+		return slowest[i].Duration > slowest[j].Duration
+	})
+
+	for i, p := range slowest {
+		Logf("slowest render #%d: %s", i+1, formatProfile(p))
+	}
+}
+
+// profileHeap is a min-heap of render profiles ordered by duration. Its root
+// is the fastest of the kept renders.
+type profileHeap []RenderProfile
+
+func (h profileHeap) Len() int {
+	return len(h)
+}
+
+func (h profileHeap) Less(i, j int) bool {
+	return h[i].Duration < h[j].Duration
+}
+
+func (h profileHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *profileHeap) Push(x interface{}) {
+	*h = append(*h, x.(RenderProfile))
+}
+
+func (h *profileHeap) Pop() interface{} {
+	old := *h
+	p := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return p
+}
+
+func formatProfile(p RenderProfile) string {
+	var tmpl time.Duration
+	for _, t := range p.Templates {
+		tmpl += t.Duration
+	}
+
+	return fmt.Sprintf("%s in %s (templates %s, sync %s of %dB): %d created, %d changed, %d deleted",
+		p.Compo,
+		p.Duration,
+		tmpl,
+		p.SyncDuration,
+		p.SyncSize,
+		p.Created,
+		p.Changed,
+		p.Deleted,
+	)
+}
+
+// traceEvent is an event of the trace event format.
+type traceEvent struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"cat"`
+	Phase     string                 `json:"ph"`
+	Timestamp int64                  `json:"ts"`
+	Duration  int64                  `json:"dur"`
+	PID       int                    `json:"pid"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+func writeTrace(filename string, profiles []RenderProfile) error {
+	events := make([]traceEvent, 0, len(profiles)*2)
+
+	for _, p := range profiles {
+		events = append(events, traceEvent{
+			Name:      p.Compo,
+			Category:  "render",
+			Phase:     "X",
+			Timestamp: micros(p.Start),
+			Duration:  int64(p.Duration / time.Microsecond),
+			PID:       1,
+			TID:       1,
+			Args: map[string]interface{}{
+				"created":  p.Created,
+				"changed":  p.Changed,
+				"deleted":  p.Deleted,
+				"syncSize": p.SyncSize,
+			},
+		})
+
+		for _, t := range p.Templates {
+			events = append(events, traceEvent{
+				Name:      t.Compo,
+				Category:  "template",
+				Phase:     "X",
+				Timestamp: micros(t.Start),
+				Duration:  int64(t.Duration / time.Microsecond),
+				PID:       1,
+				TID:       1,
+			})
+		}
+
+		if p.SyncDuration > 0 {
+			events = append(events, traceEvent{
+				Name:      "sync",
+				Category:  "sync",
+				Phase:     "X",
+				Timestamp: micros(p.Start.Add(p.Duration - p.SyncDuration)),
+				Duration:  int64(p.SyncDuration / time.Microsecond),
+				PID:       1,
+				TID:       1,
+			})
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}{
+		TraceEvents: events,
+	})
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(filename, data, 0666)
+}
+
+func micros(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Microsecond)
+}
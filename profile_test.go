@@ -0,0 +1,132 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/murlokswarm/app"
+	"github.com/murlokswarm/app/drivers/test"
+	"github.com/murlokswarm/app/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	var logs []string
+	app.Logger = func(format string, a ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, a...))
+	}
+
+	dir, err := ioutil.TempDir("", "app-profiler")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	trace := filepath.Join(dir, "trace.json")
+
+	f := app.NewFactory()
+	f.RegisterCompo(&tests.Foo{})
+	f.RegisterCompo(&tests.Bar{})
+
+	d := &test.Driver{}
+	d.OnRun = func() {
+		w := d.NewWindow(app.WindowConfig{})
+		w.Load("tests.foo")
+		assert.NoError(t, w.Err())
+		d.Stop()
+	}
+
+	profiler := app.Profiler(app.ProfilerConfig{
+		Threshold: time.Nanosecond,
+		TraceFile: trace,
+	})
+
+	profiler(d).Run(f)
+	assert.False(t, app.Profiling())
+
+	require.NotEmpty(t, logs)
+	assert.True(t, strings.HasPrefix(logs[0], "slow render: tests.foo in "))
+	assert.True(t, strings.HasPrefix(logs[len(logs)-1], "slowest render #1: tests.foo in "))
+
+	data, err := ioutil.ReadFile(trace)
+	require.NoError(t, err)
+
+	var events struct {
+		TraceEvents []struct {
+			Name     string
+			Category string `json:"cat"`
+			Phase    string `json:"ph"`
+		}
+	}
+	err = json.Unmarshal(data, &events)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, events.TraceEvents)
+	assert.Equal(t, "tests.foo", events.TraceEvents[0].Name)
+	assert.Equal(t, "render", events.TraceEvents[0].Category)
+	assert.Equal(t, "X", events.TraceEvents[0].Phase)
+}
+
+func TestProfilerBounds(t *testing.T) {
+	var logs []string
+	app.Logger = func(format string, a ...interface{}) {
+		logs = append(logs, fmt.Sprintf(format, a...))
+	}
+
+	dir, err := ioutil.TempDir("", "app-profiler")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	trace := filepath.Join(dir, "trace.json")
+
+	f := app.NewFactory()
+	f.RegisterCompo(&tests.Foo{})
+	f.RegisterCompo(&tests.Bar{})
+
+	d := &test.Driver{}
+	d.OnRun = func() {
+		for i := 0; i < 5; i++ {
+			w := d.NewWindow(app.WindowConfig{})
+			w.Load("tests.foo")
+			assert.NoError(t, w.Err())
+		}
+		d.Stop()
+	}
+
+	profiler := app.Profiler(app.ProfilerConfig{
+		Threshold: time.Hour,
+		Slowest:   2,
+		TraceFile: trace,
+		TraceSize: 3,
+	})
+
+	profiler(d).Run(f)
+
+	require.Len(t, logs, 2)
+	assert.True(t, strings.HasPrefix(logs[0], "slowest render #1: "))
+	assert.True(t, strings.HasPrefix(logs[1], "slowest render #2: "))
+
+	data, err := ioutil.ReadFile(trace)
+	require.NoError(t, err)
+
+	var events struct {
+		TraceEvents []struct {
+			Category string `json:"cat"`
+		}
+	}
+	err = json.Unmarshal(data, &events)
+	require.NoError(t, err)
+
+	renders := 0
+	for _, e := range events.TraceEvents {
+		if e.Category == "render" {
+			renders++
+		}
+	}
+	assert.Equal(t, 3, renders)
+}
@@ -0,0 +1,96 @@
+package app
+
+// Ref represents a reference to a node rendered by a component.
+// It is bound when the component renders a node with a ref attribute that
+// matches the name of a Ref field:
+//
+//	type Hello struct {
+//	    Input app.Ref
+//	}
+//
+//	func (h *Hello) Render() string {
+//	    return `<input ref="Input">`
+//	}
+//
+// Operations on a reference return ErrNotSupported when the element where the
+// component is mounted does not support node manipulations.
+type Ref struct {
+	compo  Compo
+	nodeID string
+}
+
+// NewRef creates a reference to the node with the given identifier rendered
+// by the given component.
+// It is used by rendering engines and should not be called elsewhere.
+func NewRef(c Compo, nodeID string) Ref {
+	return Ref{
+		compo:  c,
+		nodeID: nodeID,
+	}
+}
+
+// NodeID returns the identifier of the referenced node.
+func (r Ref) NodeID() string {
+	return r.nodeID
+}
+
+// IsSet reports whether the reference is bound to a node.
+func (r Ref) IsSet() bool {
+	return r.compo != nil && len(r.nodeID) != 0
+}
+
+// Focus gives the focus to the referenced node.
+func (r Ref) Focus() error {
+	return r.call("focus", nil)
+}
+
+// Blur removes the focus from the referenced node.
+func (r Ref) Blur() error {
+	return r.call("blur", nil)
+}
+
+// ScrollIntoView scrolls the referenced node parents in order to make it
+// visible.
+func (r Ref) ScrollIntoView() error {
+	return r.call("scrollIntoView", nil)
+}
+
+// BoundingRect returns the size and the position of the referenced node,
+// relative to the viewport.
+func (r Ref) BoundingRect() (Rect, error) {
+	var rect Rect
+	err := r.call("boundingRect", &rect)
+	return rect, err
+}
+
+// SetSelection selects the text of the referenced node between start and end.
+// The referenced node must be an input or a textarea.
+func (r Ref) SetSelection(start, end int) error {
+	return r.call("setSelection", nil, start, end)
+}
+
+func (r Ref) call(method string, out interface{}, args ...interface{}) error {
+	if !r.IsSet() {
+		return ErrRefNotSet
+	}
+
+	e := ElemByCompo(r.compo)
+	if err := e.Err(); err != nil {
+		return err
+	}
+
+	ec, ok := e.(ElemWithCompo)
+	if !ok {
+		return ErrNotSupported
+	}
+
+	return ec.CallNode(r.nodeID, method, out, args...)
+}
+
+// Rect represents a rectangle.
+type Rect struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
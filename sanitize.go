@@ -0,0 +1,64 @@
+package app
+
+// SanitizePolicy describes the html tags, attributes and url schemes that are
+// kept when markup is sanitized.
+// Disallowed tags are removed while their content is kept, except for tags
+// like script or style whose content is removed too. Disallowed attributes
+// and comments are removed.
+type SanitizePolicy struct {
+	// The allowed tags.
+	Tags []string
+
+	// The attributes allowed on the allowed tags.
+	Attrs []string
+
+	// The schemes allowed in attributes that contain an url, like href or
+	// src. Urls without scheme are always allowed.
+	URLSchemes []string
+
+	// Reports whether event handlers that call component fields or methods
+	// are allowed.
+	Handlers bool
+
+	// Reports whether event handlers prefixed by js: are allowed.
+	JSHandlers bool
+}
+
+// StrictSanitizePolicy returns a policy suited for untrusted content like
+// user comments. It allows text formatting, lists, tables, links and images
+// with http, https or mailto urls. Event handlers are not allowed.
+func StrictSanitizePolicy() *SanitizePolicy {
+	return &SanitizePolicy{
+		Tags: []string{
+			"a", "abbr", "b", "blockquote", "br", "caption", "cite", "code",
+			"dd", "del", "div", "dl", "dt", "em", "figcaption", "figure", "h1",
+			"h2", "h3", "h4", "h5", "h6", "hr", "i", "img", "ins", "kbd", "li",
+			"mark", "ol", "p", "pre", "q", "s", "small", "span", "strong",
+			"sub", "sup", "table", "tbody", "td", "tfoot", "th", "thead", "tr",
+			"u", "ul",
+		},
+		Attrs: []string{
+			"alt", "cite", "colspan", "datetime", "dir", "height", "href",
+			"lang", "rowspan", "src", "title", "width",
+		},
+		URLSchemes: []string{"http", "https", "mailto"},
+	}
+}
+
+// SanitizeConfig describes how the markup rendered by components is
+// sanitized. The zero value does not sanitize anything.
+type SanitizeConfig struct {
+	// The policy applied to the markup inserted with the raw template func.
+	// Raw markup is inserted as it is when nil.
+	// Use StrictSanitizePolicy() to render untrusted content.
+	Raw *SanitizePolicy
+
+	// The policy applied to all the markup rendered by components. Component
+	// tags and their attributes are not sanitized.
+	// Markup is not sanitized when nil.
+	Markup *SanitizePolicy
+
+	// Reports whether event handlers prefixed by js: are removed from all the
+	// markup.
+	DisableJSHandlers bool
+}
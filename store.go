@@ -0,0 +1,26 @@
+package app
+
+// Store is the interface that describes a persistent storage.
+// Values are small pieces of data such as settings that are encoded in JSON.
+// Blobs are raw and potentially large binary data.
+type Store interface {
+	// Get decodes the value stored under the given key into v. It returns
+	// ErrNotFound when there is no value for the key.
+	Get(key string, v interface{}) error
+
+	// Set stores the JSON encoding of v under the given key.
+	Set(key string, v interface{}) error
+
+	// Del deletes the value stored under the given key.
+	Del(key string) error
+
+	// GetBlob returns the blob stored under the given key. It returns
+	// ErrNotFound when there is no blob for the key.
+	GetBlob(key string) ([]byte, error)
+
+	// SetBlob stores the given blob under the given key.
+	SetBlob(key string, b []byte) error
+
+	// DelBlob deletes the blob stored under the given key.
+	DelBlob(key string) error
+}
@@ -0,0 +1,317 @@
+package app
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Validator is a function that validates a component field value.
+// param is the validator parameter specified in the struct tag, e.g. 3 for
+// min=3.
+// It returns an error that describes why the value is not valid.
+type Validator func(v interface{}, param string) error
+
+// Form is the type to embed in components that validate their fields.
+//
+// Validation rules are declared with the validate struct tag on exported
+// fields:
+//
+//	type Signup struct {
+//	    app.Form
+//
+//	    Name  string `validate:"required,max=32"`
+//	    Email string `validate:"required,email"`
+//	}
+//
+// Fields are validated when the component is mounted, when they are set from
+// an event and when the form is submitted. Submit handlers are not called when
+// a field is not valid. Errors are only reported for the fields that were set
+// or submitted, so they are not displayed before the user interacts with the
+// form. They are available in templates:
+//
+//	<form onsubmit="Submit">
+//	    <input onchange="Email">
+//	    <p>{{.FieldErr "Email"}}</p>
+//	    <button {{if not .Valid}}disabled{{end}}>Sign up</button>
+//	</form>
+//
+// Built-in validators are required, min, max, email and url.
+type Form struct {
+	errs      map[string]string
+	touched   map[string]bool
+	valid     bool
+	validated bool
+}
+
+// FieldErr returns the validation error of the named field. It returns an
+// empty string when the field is valid or has not been validated.
+func (f *Form) FieldErr(field string) string {
+	return f.errs[field]
+}
+
+// Valid reports whether all the fields were valid at the last validation.
+// Components are validated when they are mounted, so it reflects the initial
+// field values before the user interacts with the form.
+func (f *Form) Valid() bool {
+	return f.valid
+}
+
+func (f *Form) form() *Form {
+	return f
+}
+
+type formCompo interface {
+	form() *Form
+}
+
+// RegisterValidator registers a validator that can be used in validate struct
+// tags under the given name.
+// It panics if name is already used by a built-in validator.
+func RegisterValidator(name string, v Validator) {
+	if err := validators.Register(name, v); err != nil {
+		Panicf("registering validator %s failed: %s", name, err)
+	}
+}
+
+// Validate validates all the fields of the given component and reports
+// whether they are valid.
+// The component must embed a Form.
+func Validate(c Compo) bool {
+	return validate(c, func(string) bool { return true })
+}
+
+// ValidateField validates the named field of the given component and reports
+// whether all the component fields are valid. Errors are only reported for
+// the fields that have been validated.
+// The component must embed a Form.
+func ValidateField(c Compo, field string) bool {
+	return validate(c, func(name string) bool { return name == field })
+}
+
+// ValidateMounted validates all the fields of the given component when they
+// have never been validated, without reporting errors. It is called by the
+// dom engine before the first rendering of a component so Valid reports the
+// state of the initial field values.
+func ValidateMounted(c Compo) {
+	if fc, ok := c.(formCompo); ok && !fc.form().validated {
+		validate(c, func(string) bool { return false })
+	}
+}
+
+// validate validates the fields of the given component. Errors are reported
+// for the fields selected by touch and for the ones that were previously
+// validated.
+func validate(c Compo, touch func(field string) bool) bool {
+	fc, ok := c.(formCompo)
+	if !ok {
+		return true
+	}
+
+	f := fc.form()
+	f.validated = true
+	if f.touched == nil {
+		f.touched = make(map[string]bool)
+	}
+
+	f.errs = make(map[string]string)
+	f.valid = true
+
+	v := reflect.Indirect(reflect.ValueOf(c))
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i)
+
+		tag, ok := ft.Tag.Lookup("validate")
+		if !ok || len(ft.PkgPath) != 0 {
+			continue
+		}
+
+		if touch(ft.Name) {
+			f.touched[ft.Name] = true
+		}
+
+		if err := validators.Validate(v.Field(i).Interface(), tag); err != nil {
+			f.valid = false
+
+			if f.touched[ft.Name] {
+				f.errs[ft.Name] = err.Error()
+			}
+		}
+	}
+
+	return f.valid
+}
+
+var builtinValidators = map[string]Validator{
+	"required": validateRequired,
+	"min":      validateMin,
+	"max":      validateMax,
+	"email":    validateEmail,
+	"url":      validateURL,
+}
+
+func newValidatorRegistry() *validatorRegistry {
+	r := &validatorRegistry{
+		validators: make(map[string]Validator, len(builtinValidators)),
+	}
+
+	for name, v := range builtinValidators {
+		r.validators[name] = v
+	}
+
+	return r
+}
+
+type validatorRegistry struct {
+	mutex      sync.RWMutex
+	validators map[string]Validator
+}
+
+func (r *validatorRegistry) Register(name string, v Validator) error {
+	if _, ok := builtinValidators[name]; ok {
+		return errors.New("name is used by a built-in validator")
+	}
+
+	if v == nil {
+		return errors.New("validator is nil")
+	}
+
+	r.mutex.Lock()
+	r.validators[name] = v
+	r.mutex.Unlock()
+	return nil
+}
+
+func (r *validatorRegistry) Validate(v interface{}, tag string) error {
+	for _, rule := range strings.Split(tag, ",") {
+		rule = strings.TrimSpace(rule)
+		if len(rule) == 0 {
+			continue
+		}
+
+		name := rule
+		param := ""
+
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			name = rule[:i]
+			param = rule[i+1:]
+		}
+
+		r.mutex.RLock()
+		validator, ok := r.validators[name]
+		r.mutex.RUnlock()
+
+		if !ok {
+			return errors.Errorf("unknown validator %s", name)
+		}
+
+		if err := validator(v, param); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRequired(v interface{}, param string) error {
+	if rv := reflect.ValueOf(v); !rv.IsValid() || rv.IsZero() {
+		return errors.New("is required")
+	}
+
+	return nil
+}
+
+func validateMin(v interface{}, param string) error {
+	min, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return errors.Errorf("bad min parameter %q", param)
+	}
+
+	n, unit, ok := size(v)
+	if !ok {
+		return errors.Errorf("min does not support %T", v)
+	}
+
+	if n < min {
+		return errors.Errorf("must be at least %s%s", param, unit)
+	}
+
+	return nil
+}
+
+func validateMax(v interface{}, param string) error {
+	max, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return errors.Errorf("bad max parameter %q", param)
+	}
+
+	n, unit, ok := size(v)
+	if !ok {
+		return errors.Errorf("max does not support %T", v)
+	}
+
+	if n > max {
+		return errors.Errorf("must be at most %s%s", param, unit)
+	}
+
+	return nil
+}
+
+// size returns the size of the given value that is compared by min and max:
+// the length of strings, slices and maps or the value of numbers.
+func size(v interface{}) (n float64, unit string, ok bool) {
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() {
+	case reflect.String:
+		return float64(len([]rune(rv.String()))), " characters", true
+
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(rv.Len()), " elements", true
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), "", true
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), "", true
+
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), "", true
+
+	default:
+		return 0, "", false
+	}
+}
+
+var emailRegexp = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+
+func validateEmail(v interface{}, param string) error {
+	s := fmt.Sprint(v)
+
+	if len(s) != 0 && !emailRegexp.MatchString(s) {
+		return errors.New("is not a valid email address")
+	}
+
+	return nil
+}
+
+func validateURL(v interface{}, param string) error {
+	s := fmt.Sprint(v)
+	if len(s) == 0 {
+		return nil
+	}
+
+	if u, err := url.Parse(s); err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return errors.New("is not a valid url")
+	}
+
+	return nil
+}
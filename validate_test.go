@@ -0,0 +1,132 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type Signup struct {
+	Form
+
+	Name    string `validate:"required,min=2,max=8"`
+	Email   string `validate:"email"`
+	Age     int    `validate:"min=18"`
+	Website string `validate:"url"`
+	Code    string `validate:"even"`
+	Notes   string
+}
+
+func (s *Signup) Render() string {
+	return `<form></form>`
+}
+
+func TestValidators(t *testing.T) {
+	tests := []struct {
+		scenario string
+		value    interface{}
+		tag      string
+		err      bool
+	}{
+		{scenario: "required", value: "hello", tag: "required"},
+		{scenario: "required zero", value: 0, tag: "required", err: true},
+		{scenario: "required nil", value: nil, tag: "required", err: true},
+		{scenario: "min string", value: "héllo", tag: "min=5"},
+		{scenario: "min string too short", value: "hé", tag: "min=3", err: true},
+		{scenario: "min number", value: 21, tag: "min=18"},
+		{scenario: "min number too low", value: 2.5, tag: "min=3", err: true},
+		{scenario: "min slice too short", value: []int{}, tag: "min=1", err: true},
+		{scenario: "min bad param", value: 21, tag: "min=x", err: true},
+		{scenario: "min unsupported", value: struct{}{}, tag: "min=1", err: true},
+		{scenario: "max", value: uint(3), tag: "max=3"},
+		{scenario: "max too high", value: map[int]int{1: 1}, tag: "max=0", err: true},
+		{scenario: "email", value: "max@example.com", tag: "email"},
+		{scenario: "email empty", value: "", tag: "email"},
+		{scenario: "email invalid", value: "max@", tag: "email", err: true},
+		{scenario: "url", value: "https://example.com", tag: "url"},
+		{scenario: "url invalid", value: "example.com", tag: "url", err: true},
+		{scenario: "multiple rules", value: "", tag: "email, required", err: true},
+		{scenario: "unknown validator", value: "", tag: "unknown", err: true},
+	}
+
+	r := newValidatorRegistry()
+
+	for _, test := range tests {
+		t.Run(test.scenario, func(t *testing.T) {
+			err := r.Validate(test.value, test.tag)
+
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	RegisterValidator("even", func(v interface{}, param string) error {
+		if len(v.(string))%2 != 0 {
+			return errors.New("must have an even length")
+		}
+		return nil
+	})
+
+	s := &Signup{
+		Name:  "x",
+		Email: "bad",
+		Age:   18,
+	}
+
+	assert.False(t, s.Valid())
+
+	assert.False(t, ValidateField(s, "Name"))
+	assert.NotEmpty(t, s.FieldErr("Name"))
+	assert.Empty(t, s.FieldErr("Email"))
+
+	s.Name = "Maxence"
+	assert.False(t, ValidateField(s, "Name"))
+	assert.Empty(t, s.FieldErr("Name"))
+
+	assert.False(t, Validate(s))
+	assert.NotEmpty(t, s.FieldErr("Email"))
+
+	s.Email = "max@example.com"
+	s.Code = "ab"
+	assert.True(t, Validate(s))
+	assert.True(t, s.Valid())
+
+	s.Code = "abc"
+	assert.False(t, ValidateField(s, "Code"))
+	assert.Equal(t, "must have an even length", s.FieldErr("Code"))
+
+	assert.True(t, Validate(&VirtualList{}))
+}
+
+func TestValidateMounted(t *testing.T) {
+	s := &Signup{Name: "x", Age: 18}
+	ValidateMounted(s)
+	assert.False(t, s.Valid())
+	assert.Empty(t, s.FieldErr("Name"))
+
+	s.Name = "Maxence"
+	ValidateMounted(s)
+	assert.False(t, s.Valid())
+
+	assert.True(t, ValidateField(s, "Name"))
+	assert.True(t, s.Valid())
+
+	s = &Signup{Name: "Maxence", Age: 18}
+	ValidateMounted(s)
+	assert.True(t, s.Valid())
+
+	ValidateMounted(&VirtualList{})
+}
+
+func TestRegisterValidator(t *testing.T) {
+	defer func() { recover() }()
+	RegisterValidator("required", nil)
+	assert.Fail(t, "no panic")
+}